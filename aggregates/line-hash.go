@@ -0,0 +1,20 @@
+package aggregates
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Hash identifying a set of skaters, as stored in play_by_play_on_ice and
+// team_season_skater_lines: md5 of the sorted ids joined by "-"
+func LineHash(skaterIds []int) string {
+	sorted := append([]int(nil), skaterIds...)
+	sort.Ints(sorted)
+
+	line := strings.Trim(strings.Join(strings.Fields(fmt.Sprint(sorted)), "-"), "[]")
+	hash := md5.Sum([]byte(line))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,315 @@
+package aggregates
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type lineKey struct {
+	teamId   int
+	lineHash string
+}
+
+// How a skater line performed over a season
+type LineSeasonPerformance struct {
+	Season     string
+	TeamId     int
+	LineHash   string
+	Events     int
+	CF         int
+	CA         int
+	GF         int
+	GA         int
+	ToiSeconds int
+}
+
+// Share of on-ice goals scored by the line, or 0 when no goals were scored
+func (l LineSeasonPerformance) GoalsForPct() float64 {
+	if l.GF+l.GA == 0 {
+		return 0
+	}
+	return float64(l.GF) / float64(l.GF+l.GA)
+}
+
+type shiftSpan struct {
+	playerId int
+	start    int
+	end      int
+}
+
+// Rebuilds team_season_line_performance for a season from play_by_play_on_ice
+// and, where loaded, the shifts table
+func BuildLineSeasonPerformance(db *sql.DB, season string) error {
+	totals := make(map[lineKey]*LineSeasonPerformance)
+	line := func(key lineKey) *LineSeasonPerformance {
+		record, ok := totals[key]
+		if !ok {
+			record = &LineSeasonPerformance{Season: season, TeamId: key.teamId, LineHash: key.lineHash}
+			totals[key] = record
+		}
+		return record
+	}
+
+	if err := addLineEvents(db, season, line); err != nil {
+		return err
+	}
+	if err := addLineToi(db, season, line); err != nil {
+		return err
+	}
+
+	records := make([]LineSeasonPerformance, 0, len(totals))
+	for _, record := range totals {
+		records = append(records, *record)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM team_season_line_performance WHERE season = ?", season); err != nil {
+		return fmt.Errorf("failed to delete team_season_line_performance for season %s: %w", season, err)
+	}
+
+	const batchSize = 1000
+	for i := 0; i < len(records); i += batchSize {
+		end := i + batchSize
+		if end > len(records) {
+			end = len(records)
+		}
+		if err := insertLineSeasonPerformance(tx, records[i:end]); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit team_season_line_performance for season %s: %w", season, err)
+	}
+
+	fmt.Printf("Inserted %d records into team_season_line_performance for season %s\n", len(records), season)
+	return nil
+}
+
+func addLineEvents(db *sql.DB, season string, line func(lineKey) *LineSeasonPerformance) error {
+	rows, err := db.Query(`
+	SELECT oi.team_id, oi.line_hash, pbp.event_type_id, pbp.team_id
+	FROM play_by_play_on_ice oi
+	JOIN play_by_play pbp ON pbp.game_pk = oi.game_pk AND pbp.event_idx = oi.event_idx
+	JOIN games g ON g.game_pk = oi.game_pk
+	WHERE g.season = ? AND pbp.period_type <> 'SHOOTOUT'`, season)
+	if err != nil {
+		return fmt.Errorf("failed to query line events for season %s: %w", season, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key lineKey
+		var eventTypeId string
+		var eventTeamId sql.NullInt64
+		if err := rows.Scan(&key.teamId, &key.lineHash, &eventTypeId, &eventTeamId); err != nil {
+			return fmt.Errorf("failed to scan line event for season %s: %w", season, err)
+		}
+
+		record := line(key)
+		record.Events++
+
+		switch eventTypeId {
+		case "SHOT", "MISSED_SHOT", "GOAL", "BLOCKED_SHOT":
+		default:
+			continue
+		}
+
+		// Blocked shots carry the blocking team, so they count against the line
+		isFor := int(eventTeamId.Int64) == key.teamId
+		if eventTypeId == "BLOCKED_SHOT" {
+			isFor = !isFor
+		}
+
+		if isFor {
+			record.CF++
+			record.GF += boolToInt(eventTypeId == "GOAL")
+		} else {
+			record.CA++
+			record.GA += boolToInt(eventTypeId == "GOAL")
+		}
+	}
+
+	return rows.Err()
+}
+
+// Credits each line with the time its exact set of skaters shared the ice
+func addLineToi(db *sql.DB, season string, line func(lineKey) *LineSeasonPerformance) error {
+	goalies, err := fetchSeasonGoalies(db, season)
+	if err != nil {
+		return err
+	}
+
+	rows, err := db.Query(`
+	SELECT s.game_pk, s.team_id, s.period, s.player_id, s.start_seconds, s.end_seconds
+	FROM shifts s
+	JOIN games g ON g.game_pk = s.game_pk
+	WHERE g.season = ?
+	ORDER BY s.game_pk, s.team_id, s.period`, season)
+	if err != nil {
+		return fmt.Errorf("failed to query shifts for season %s: %w", season, err)
+	}
+	defer rows.Close()
+
+	type periodKey struct {
+		gamePk int
+		teamId int
+		period int
+	}
+
+	var current periodKey
+	var spans []shiftSpan
+
+	flush := func() {
+		for lineHash, seconds := range lineToi(spans) {
+			line(lineKey{current.teamId, lineHash}).ToiSeconds += seconds
+		}
+		spans = spans[:0]
+	}
+
+	for rows.Next() {
+		var key periodKey
+		var span shiftSpan
+		if err := rows.Scan(&key.gamePk, &key.teamId, &key.period, &span.playerId, &span.start, &span.end); err != nil {
+			return fmt.Errorf("failed to scan shift for season %s: %w", season, err)
+		}
+
+		if key != current {
+			flush()
+			current = key
+		}
+		if !goalies[span.playerId] {
+			spans = append(spans, span)
+		}
+	}
+	flush()
+
+	return rows.Err()
+}
+
+// Splits a team's period into segments between shift changes and totals the
+// seconds each skater set was on the ice
+func lineToi(spans []shiftSpan) map[string]int {
+	boundarySet := make(map[int]bool)
+	for _, span := range spans {
+		boundarySet[span.start] = true
+		boundarySet[span.end] = true
+	}
+
+	boundaries := make([]int, 0, len(boundarySet))
+	for boundary := range boundarySet {
+		boundaries = append(boundaries, boundary)
+	}
+	sort.Ints(boundaries)
+
+	toi := make(map[string]int)
+	for i := 0; i+1 < len(boundaries); i++ {
+		start, end := boundaries[i], boundaries[i+1]
+
+		var skaterIds []int
+		for _, span := range spans {
+			if span.start <= start && span.end >= end {
+				skaterIds = append(skaterIds, span.playerId)
+			}
+		}
+		if len(skaterIds) == 0 {
+			continue
+		}
+
+		toi[LineHash(skaterIds)] += end - start
+	}
+
+	return toi
+}
+
+func fetchSeasonGoalies(db *sql.DB, season string) (map[int]bool, error) {
+	rows, err := db.Query(`
+	SELECT DISTINCT oi.goalie_id
+	FROM play_by_play_on_ice oi
+	JOIN games g ON g.game_pk = oi.game_pk
+	WHERE g.season = ? AND oi.goalie_id <> 0`, season)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query goalies for season %s: %w", season, err)
+	}
+	defer rows.Close()
+
+	goalies := make(map[int]bool)
+	for rows.Next() {
+		var goalieId int
+		if err := rows.Scan(&goalieId); err != nil {
+			return nil, fmt.Errorf("failed to scan goalie for season %s: %w", season, err)
+		}
+		goalies[goalieId] = true
+	}
+
+	return goalies, rows.Err()
+}
+
+func insertLineSeasonPerformance(tx *sql.Tx, records []LineSeasonPerformance) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	valueStrings := make([]string, 0, len(records))
+	valueArgs := make([]interface{}, 0, len(records)*9)
+
+	for _, r := range records {
+		valueStrings = append(valueStrings, "(?, ?, ?, ?, ?, ?, ?, ?, ?)")
+		valueArgs = append(valueArgs,
+			r.Season,
+			r.TeamId,
+			r.LineHash,
+			r.Events,
+			r.CF,
+			r.CA,
+			r.GF,
+			r.GA,
+			r.ToiSeconds,
+		)
+	}
+
+	stmt := fmt.Sprintf(`
+	INSERT INTO team_season_line_performance
+	(season, team_id, line_hash, events, cf, ca, gf, ga, toi_seconds)
+	VALUES %s`, strings.Join(valueStrings, ","))
+
+	if _, err := tx.Exec(stmt, valueArgs...); err != nil {
+		return fmt.Errorf("failed to insert team_season_line_performance: %w", err)
+	}
+
+	return nil
+}
+
+// Returns every line the player skated on in a season, best goal share first
+func LinesForPlayer(db *sql.DB, season string, playerId int) ([]LineSeasonPerformance, error) {
+	rows, err := db.Query(`
+	SELECT p.season, p.team_id, p.line_hash, p.events, p.cf, p.ca, p.gf, p.ga, p.toi_seconds
+	FROM team_season_line_performance p
+	JOIN team_season_skater_lines l
+		ON l.season = p.season AND l.team_id = p.team_id AND l.line_hash = p.line_hash
+	WHERE p.season = ?
+		AND ? IN (l.skater_id_1, l.skater_id_2, l.skater_id_3, l.skater_id_4, l.skater_id_5, l.skater_id_6)
+	ORDER BY p.gf / NULLIF(p.gf + p.ga, 0) DESC, p.toi_seconds DESC`, season, playerId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query lines for player %d: %w", playerId, err)
+	}
+	defer rows.Close()
+
+	var lines []LineSeasonPerformance
+	for rows.Next() {
+		var l LineSeasonPerformance
+		if err := rows.Scan(&l.Season, &l.TeamId, &l.LineHash, &l.Events, &l.CF, &l.CA, &l.GF, &l.GA, &l.ToiSeconds); err != nil {
+			return nil, fmt.Errorf("failed to scan line for player %d: %w", playerId, err)
+		}
+		lines = append(lines, l)
+	}
+
+	return lines, rows.Err()
+}
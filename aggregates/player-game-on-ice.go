@@ -0,0 +1,248 @@
+package aggregates
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/gavswe19/ice-pipelines/database"
+	"github.com/gavswe19/ice-pipelines/xg"
+)
+
+type onIceKey struct {
+	playerId      int
+	teamId        int
+	strengthState string
+}
+
+// On-ice shot and goal counts for one player in one game at one strength
+type PlayerGameOnIce struct {
+	GamePk        int
+	PlayerId      int
+	TeamId        int
+	StrengthState string
+	CF            int
+	CA            int
+	FF            int
+	FA            int
+	SF            int
+	SA            int
+	GF            int
+	GA            int
+	XGF           float64
+	XGA           float64
+}
+
+type shotEvent struct {
+	eventIdx      int
+	eventTypeId   string
+	teamId        int
+	strengthState string
+	xg            float64
+}
+
+type onIcePlayers struct {
+	teamId    int
+	playerIds []int
+}
+
+// Rebuilds player_game_on_ice for a game from its play_by_play, on-ice and
+// shot_xg rows. Run it in the unit of work that writes those rows so the
+// aggregate commits with them.
+func BuildPlayerGameOnIce(q database.Queryer, gamePk int) error {
+	shots, err := fetchShotEvents(q, gamePk)
+	if err != nil {
+		return err
+	}
+
+	onIce, err := fetchOnIcePlayers(q, gamePk)
+	if err != nil {
+		return err
+	}
+
+	records := aggregatePlayerGameOnIce(gamePk, shots, onIce)
+
+	if _, err := q.Exec("DELETE FROM player_game_on_ice WHERE game_pk = ?", gamePk); err != nil {
+		return fmt.Errorf("failed to delete player_game_on_ice for gamePk %d: %w", gamePk, err)
+	}
+
+	if err := insertPlayerGameOnIce(q, records); err != nil {
+		return err
+	}
+
+	fmt.Printf("Inserted %d records into player_game_on_ice for gamePk %d\n", len(records), gamePk)
+	return nil
+}
+
+func aggregatePlayerGameOnIce(gamePk int, shots []shotEvent, onIce map[int][]onIcePlayers) []PlayerGameOnIce {
+	totals := make(map[onIceKey]*PlayerGameOnIce)
+
+	for _, shot := range shots {
+		teams := onIce[shot.eventIdx]
+		if len(teams) != 2 {
+			continue
+		}
+		shooterTeamId := xg.ShootingTeamId(shot.eventTypeId, shot.teamId, [2]int{teams[0].teamId, teams[1].teamId})
+
+		for _, team := range teams {
+			strengthState := shot.strengthState
+			if team.teamId != shot.teamId {
+				strengthState = flipStrength(strengthState)
+			}
+			isFor := team.teamId == shooterTeamId
+
+			for _, playerId := range team.playerIds {
+				key := onIceKey{playerId, team.teamId, strengthState}
+				record, ok := totals[key]
+				if !ok {
+					record = &PlayerGameOnIce{
+						GamePk:        gamePk,
+						PlayerId:      playerId,
+						TeamId:        team.teamId,
+						StrengthState: strengthState,
+					}
+					totals[key] = record
+				}
+				record.add(shot, isFor)
+			}
+		}
+	}
+
+	records := make([]PlayerGameOnIce, 0, len(totals))
+	for _, record := range totals {
+		records = append(records, *record)
+	}
+	return records
+}
+
+func (r *PlayerGameOnIce) add(shot shotEvent, isFor bool) {
+	unblocked := shot.eventTypeId != "BLOCKED_SHOT"
+	onGoal := shot.eventTypeId == "SHOT" || shot.eventTypeId == "GOAL"
+	goal := shot.eventTypeId == "GOAL"
+
+	if isFor {
+		r.CF++
+		r.FF += boolToInt(unblocked)
+		r.SF += boolToInt(onGoal)
+		r.GF += boolToInt(goal)
+		r.XGF += shot.xg
+		return
+	}
+
+	r.CA++
+	r.FA += boolToInt(unblocked)
+	r.SA += boolToInt(onGoal)
+	r.GA += boolToInt(goal)
+	r.XGA += shot.xg
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func fetchShotEvents(q database.Queryer, gamePk int) ([]shotEvent, error) {
+	rows, err := q.Query(`
+	SELECT pbp.event_idx, pbp.event_type_id, pbp.team_id, pbp.strength_state, sx.xg
+	FROM play_by_play pbp
+	LEFT JOIN shot_xg sx ON sx.game_pk = pbp.game_pk AND sx.event_idx = pbp.event_idx
+	WHERE pbp.game_pk = ?
+		AND pbp.event_type_id IN ('SHOT', 'MISSED_SHOT', 'BLOCKED_SHOT', 'GOAL')
+		AND pbp.period_type <> 'SHOOTOUT'`, gamePk)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query shots for gamePk %d: %w", gamePk, err)
+	}
+	defer rows.Close()
+
+	var shots []shotEvent
+	for rows.Next() {
+		var shot shotEvent
+		var strengthState sql.NullString
+		var xg sql.NullFloat64
+		if err := rows.Scan(&shot.eventIdx, &shot.eventTypeId, &shot.teamId, &strengthState, &xg); err != nil {
+			return nil, fmt.Errorf("failed to scan shot for gamePk %d: %w", gamePk, err)
+		}
+		shot.strengthState = strengthState.String
+		shot.xg = xg.Float64
+		shots = append(shots, shot)
+	}
+
+	return shots, rows.Err()
+}
+
+// Returns the skaters and goalie each team had on the ice, keyed by eventIdx
+func fetchOnIcePlayers(q database.Queryer, gamePk int) (map[int][]onIcePlayers, error) {
+	rows, err := q.Query(`
+	SELECT oi.event_idx, oi.team_id, oi.goalie_id,
+		l.skater_id_1, l.skater_id_2, l.skater_id_3, l.skater_id_4, l.skater_id_5, l.skater_id_6
+	FROM play_by_play_on_ice oi
+	JOIN games g ON g.game_pk = oi.game_pk
+	LEFT JOIN team_season_skater_lines l
+		ON l.season = g.season AND l.team_id = oi.team_id AND l.line_hash = oi.line_hash
+	WHERE oi.game_pk = ?`, gamePk)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query on-ice players for gamePk %d: %w", gamePk, err)
+	}
+	defer rows.Close()
+
+	onIce := make(map[int][]onIcePlayers)
+	for rows.Next() {
+		var eventIdx int
+		var team onIcePlayers
+		playerIds := make([]sql.NullInt64, 7)
+		if err := rows.Scan(&eventIdx, &team.teamId, &playerIds[0], &playerIds[1], &playerIds[2], &playerIds[3], &playerIds[4], &playerIds[5], &playerIds[6]); err != nil {
+			return nil, fmt.Errorf("failed to scan on-ice players for gamePk %d: %w", gamePk, err)
+		}
+
+		for _, playerId := range playerIds {
+			if playerId.Valid && playerId.Int64 != 0 {
+				team.playerIds = append(team.playerIds, int(playerId.Int64))
+			}
+		}
+		onIce[eventIdx] = append(onIce[eventIdx], team)
+	}
+
+	return onIce, rows.Err()
+}
+
+func insertPlayerGameOnIce(exec database.Execer, records []PlayerGameOnIce) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	valueStrings := make([]string, 0, len(records))
+	valueArgs := make([]interface{}, 0, len(records)*14)
+
+	for _, r := range records {
+		valueStrings = append(valueStrings, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
+		valueArgs = append(valueArgs,
+			r.GamePk,
+			r.PlayerId,
+			r.TeamId,
+			r.StrengthState,
+			r.CF,
+			r.CA,
+			r.FF,
+			r.FA,
+			r.SF,
+			r.SA,
+			r.GF,
+			r.GA,
+			r.XGF,
+			r.XGA,
+		)
+	}
+
+	stmt := fmt.Sprintf(`
+	INSERT INTO player_game_on_ice
+	(game_pk, player_id, team_id, strength_state, cf, ca, ff, fa, sf, sa, gf, ga, xgf, xga)
+	VALUES %s`, strings.Join(valueStrings, ","))
+
+	if _, err := exec.Exec(stmt, valueArgs...); err != nil {
+		return fmt.Errorf("failed to insert player_game_on_ice: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package aggregates
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// Rebuilds player_season_zone_starts for a season from faceoff_zone_starts.
+// oz_start_pct is offensive zone starts over offensive plus defensive zone
+// starts, leaving neutral zone draws out as is conventional.
+func BuildPlayerSeasonZoneStarts(db *sql.DB, season string) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM player_season_zone_starts WHERE season = ?", season); err != nil {
+		return fmt.Errorf("failed to delete player_season_zone_starts for season %s: %w", season, err)
+	}
+
+	result, err := tx.Exec(`
+	INSERT INTO player_season_zone_starts
+	(season, player_id, team_id, oz_starts, dz_starts, nz_starts, oz_start_pct)
+	SELECT g.season, zs.player_id, zs.team_id,
+		SUM(zs.zone = 'O'), SUM(zs.zone = 'D'), SUM(zs.zone = 'N'),
+		SUM(zs.zone = 'O') / NULLIF(SUM(zs.zone IN ('O', 'D')), 0)
+	FROM faceoff_zone_starts zs
+	JOIN games g ON g.game_pk = zs.game_pk
+	WHERE g.season = ?
+	GROUP BY g.season, zs.player_id, zs.team_id`, season)
+	if err != nil {
+		return fmt.Errorf("failed to insert player_season_zone_starts for season %s: %w", season, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit player_season_zone_starts for season %s: %w", season, err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	fmt.Printf("Inserted %d records into player_season_zone_starts for season %s\n", rowsAffected, season)
+	return nil
+}
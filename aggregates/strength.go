@@ -0,0 +1,13 @@
+package aggregates
+
+import "strings"
+
+// play_by_play.strength_state is labelled from the event team's perspective;
+// this returns it from the other team's perspective
+func flipStrength(strengthState string) string {
+	parts := strings.Split(strengthState, "v")
+	if len(parts) != 2 {
+		return strengthState
+	}
+	return parts[1] + "v" + parts[0]
+}
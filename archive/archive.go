@@ -0,0 +1,181 @@
+package archive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// Where payloads are archived, e.g. file:///var/ice-archive or
+	// s3://bucket/prefix. Archiving is off when unset.
+	URLEnv = "ARCHIVE_URL"
+	// Set to "replay" to read payloads from the archive instead of the API
+	ModeEnv = "ARCHIVE_MODE"
+	// Endpoint of an S3-compatible store other than AWS
+	S3EndpointEnv = "ARCHIVE_S3_ENDPOINT"
+)
+
+const fetchedAtFormat = "20060102T150405.000Z"
+
+var ErrNotFound = errors.New("payload not found in archive")
+
+// Blob store holding archived payloads
+type Store interface {
+	Put(ctx context.Context, key string, data []byte) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Returns every key beginning with prefix
+	List(ctx context.Context, prefix string) ([]string, error)
+}
+
+// Raw API payloads keyed by endpoint, parameters and fetch time. A nil
+// *Archive is valid and passes every fetch straight through.
+type Archive struct {
+	store  Store
+	replay bool
+}
+
+func New(store Store, replay bool) *Archive {
+	return &Archive{store: store, replay: replay}
+}
+
+// Builds the archive described by the environment, or nil when ARCHIVE_URL
+// is unset
+func FromEnv(ctx context.Context) (*Archive, error) {
+	replay := os.Getenv(ModeEnv) == "replay"
+
+	location := os.Getenv(URLEnv)
+	if location == "" {
+		if replay {
+			return nil, fmt.Errorf("%s=replay requires %s", ModeEnv, URLEnv)
+		}
+		return nil, nil
+	}
+
+	store, err := OpenStore(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+	return New(store, replay), nil
+}
+
+func OpenStore(ctx context.Context, location string) (Store, error) {
+	storeUrl, err := url.Parse(location)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s %q: %w", URLEnv, location, err)
+	}
+
+	switch storeUrl.Scheme {
+	case "file", "":
+		return NewFileStore(storeUrl.Path), nil
+	case "s3":
+		return NewS3Store(ctx, storeUrl.Host, strings.TrimPrefix(storeUrl.Path, "/"), os.Getenv(S3EndpointEnv))
+	}
+	return nil, fmt.Errorf("unsupported archive scheme %q", storeUrl.Scheme)
+}
+
+func (a *Archive) Replaying() bool {
+	return a != nil && a.replay
+}
+
+// Returns the payload for endpoint and params. In replay mode this is the
+// latest archived copy; otherwise fetch is called and its payload archived.
+// Failing to archive does not fail the fetch.
+func (a *Archive) Fetch(ctx context.Context, endpoint string, params url.Values, fetch func() ([]byte, error)) ([]byte, error) {
+	if a == nil {
+		return fetch()
+	}
+
+	if a.replay {
+		data, _, err := a.Latest(ctx, endpoint, params)
+		return data, err
+	}
+
+	data, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+
+	err = a.Save(ctx, endpoint, params, time.Now().UTC(), data)
+	if err != nil {
+		fmt.Println("Error archiving payload:", err)
+	}
+	return data, nil
+}
+
+func (a *Archive) Save(ctx context.Context, endpoint string, params url.Values, fetchedAt time.Time, data []byte) error {
+	key := payloadPrefix(endpoint, params) + fetchedAt.UTC().Format(fetchedAtFormat) + ".json"
+	return a.store.Put(ctx, key, data)
+}
+
+// Returns the most recently fetched payload for endpoint and params
+func (a *Archive) Latest(ctx context.Context, endpoint string, params url.Values) ([]byte, time.Time, error) {
+	prefix := payloadPrefix(endpoint, params)
+	keys, err := a.store.List(ctx, prefix)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	var latestKey string
+	var latest time.Time
+	for _, key := range keys {
+		fetchedAt, err := time.Parse(fetchedAtFormat, strings.TrimSuffix(strings.TrimPrefix(key, prefix), ".json"))
+		if err != nil {
+			continue
+		}
+		if latestKey == "" || fetchedAt.After(latest) {
+			latestKey, latest = key, fetchedAt
+		}
+	}
+
+	if latestKey == "" {
+		return nil, time.Time{}, fmt.Errorf("%w: %s", ErrNotFound, prefix)
+	}
+
+	data, err := a.store.Get(ctx, latestKey)
+	return data, latest, err
+}
+
+// Returns the distinct integer values of param across every archived
+// payload of endpoint, e.g. every gamePk with an archived feed
+func (a *Archive) Ids(ctx context.Context, endpoint string, param string) ([]int, error) {
+	keys, err := a.store.List(ctx, endpoint+"/")
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[int]bool{}
+	ids := []int{}
+	for _, key := range keys {
+		parts := strings.Split(strings.TrimPrefix(key, endpoint+"/"), "/")
+		params, err := url.ParseQuery(parts[0])
+		if err != nil {
+			continue
+		}
+		id, err := strconv.Atoi(params.Get(param))
+		if err != nil || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+
+	sort.Ints(ids)
+	return ids, nil
+}
+
+// Parameters are encoded in sorted order so the same request always lands
+// under the same prefix
+func payloadPrefix(endpoint string, params url.Values) string {
+	encoded := params.Encode()
+	if encoded == "" {
+		encoded = "_"
+	}
+	return endpoint + "/" + encoded + "/"
+}
@@ -7,7 +7,7 @@ package aws_secrets
 import (
 	"context"
 	"encoding/json"
-	"log"
+	"fmt"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
@@ -20,13 +20,13 @@ type Secrets struct {
 	Host     string `json:"host"`
 }
 
-func GetAwsSecrets() (secrets Secrets) {
+func GetAwsSecrets() (secrets Secrets, err error) {
 	secretName := "farm/mysql"
 	region := "us-east-1"
 
 	config, err := config.LoadDefaultConfig(context.TODO(), config.WithRegion(region))
 	if err != nil {
-		log.Fatal(err)
+		return
 	}
 
 	// Create Secrets Manager client
@@ -41,13 +41,14 @@ func GetAwsSecrets() (secrets Secrets) {
 	if err != nil {
 		// For a list of exceptions thrown, see
 		// https://docs.aws.amazon.com/secretsmanager/latest/apireference/API_GetSecretValue.html
-		log.Fatal(err.Error())
+		err = fmt.Errorf("failed to read secret %s: %w", secretName, err)
+		return
 	}
 
 	// Decrypts secret using the associated KMS key.
 	secretString := *result.SecretString
 
-	json.Unmarshal([]byte(secretString), &secrets)
+	err = json.Unmarshal([]byte(secretString), &secrets)
 
 	return
 }
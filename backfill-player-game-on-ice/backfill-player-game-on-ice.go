@@ -0,0 +1,61 @@
+package main
+
+import (
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/gavswe19/ice-pipelines/aggregates"
+	"github.com/gavswe19/ice-pipelines/database"
+)
+
+// Rebuilds player_game_on_ice for every game of a season
+func main() {
+	season := flag.String("season", "20232024", "season to rebuild, e.g. 20232024")
+	flag.Parse()
+
+	db, err := database.GetDatabase()
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	gamePkList, err := fetchSeasonGames(db, *season)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Rebuilding player_game_on_ice for %d games in %s\n", len(gamePkList), *season)
+
+	failed := 0
+	for _, gamePk := range gamePkList {
+		err := database.RunUnitOfWork(db, func(uow *database.UnitOfWork) error {
+			return aggregates.BuildPlayerGameOnIce(uow, gamePk)
+		})
+		if err != nil {
+			fmt.Println("Error:", err)
+			failed++
+		}
+	}
+
+	fmt.Printf("Finished with %d failed games\n", failed)
+}
+
+func fetchSeasonGames(db *sql.DB, season string) ([]int, error) {
+	results, err := db.Query("SELECT game_pk FROM games WHERE season = ? ORDER BY game_pk", season)
+
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving games: %w", err)
+	}
+	defer results.Close()
+
+	gamePkList := []int{}
+
+	for results.Next() {
+		var gamePk int
+		err = results.Scan(&gamePk)
+		if err != nil {
+			return nil, err
+		}
+		gamePkList = append(gamePkList, gamePk)
+	}
+	return gamePkList, results.Err()
+}
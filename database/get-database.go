@@ -2,14 +2,16 @@ package database
 
 import (
 	"database/sql"
-	"log"
 
 	aws_secrets "github.com/gavswe19/ice-pipelines/aws-secrets"
 	"github.com/go-sql-driver/mysql"
 )
 
-func GetDatabase() *sql.DB {
-	secrets := aws_secrets.GetAwsSecrets()
+func GetDatabase() (*sql.DB, error) {
+	secrets, err := aws_secrets.GetAwsSecrets()
+	if err != nil {
+		return nil, err
+	}
 
 	cfg := mysql.Config{
 		User:                 secrets.Username,
@@ -23,8 +25,8 @@ func GetDatabase() *sql.DB {
 
 	db, err := sql.Open("mysql", cfg.FormatDSN())
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 
-	return db
+	return db, nil
 }
@@ -2,14 +2,16 @@ package database
 
 import (
 	"database/sql"
-	"log"
 
 	aws_secrets "github.com/gavswe19/ice-pipelines/aws-secrets"
 	"github.com/go-sql-driver/mysql"
 )
 
-func GetTransaction() *sql.Tx {
-	secrets := aws_secrets.GetAwsSecrets()
+func GetTransaction() (*sql.Tx, error) {
+	secrets, err := aws_secrets.GetAwsSecrets()
+	if err != nil {
+		return nil, err
+	}
 
 	cfg := mysql.Config{
 		User:                 secrets.Username,
@@ -22,13 +24,13 @@ func GetTransaction() *sql.Tx {
 
 	db, err := sql.Open("mysql", cfg.FormatDSN())
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 
 	tx, err := db.Begin()
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 
-	return tx
+	return tx, nil
 }
@@ -0,0 +1,58 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+)
+
+// Implemented by *sql.DB, *sql.Tx and *UnitOfWork
+type Execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// Implemented by *sql.DB, *sql.Tx and *UnitOfWork, for work that reads rows
+// written earlier in the same unit
+type Queryer interface {
+	Execer
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// Writes that commit or roll back together. Writers take a *UnitOfWork
+// rather than the connection so nothing can be written beside it.
+type UnitOfWork struct {
+	tx *sql.Tx
+}
+
+func (u *UnitOfWork) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return u.tx.Exec(query, args...)
+}
+
+func (u *UnitOfWork) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return u.tx.Query(query, args...)
+}
+
+// Runs work in a single transaction. It commits if work returns nil and
+// rolls back otherwise; the returned error is work's or the commit's.
+func RunUnitOfWork(db *sql.DB, work func(uow *UnitOfWork) error) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	println("Start Transaction")
+
+	err = work(&UnitOfWork{tx: tx})
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			return errors.Join(err, rollbackErr)
+		}
+		println("Rolled back Transaction")
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+	println("Committed Transaction")
+	return nil
+}
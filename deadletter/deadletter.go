@@ -0,0 +1,77 @@
+package deadletter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/gavswe19/ice-pipelines/failure"
+)
+
+// Environment variable holding the url of the dead-letter queue
+const QueueUrlEnv = "DEAD_LETTER_QUEUE_URL"
+
+// Sends a message that can never succeed to the dead-letter queue with the
+// failure reason and its source attached as message attributes.
+func Send(ctx context.Context, record events.SQSMessage, cause error) error {
+	queueUrl := os.Getenv(QueueUrlEnv)
+	if queueUrl == "" {
+		return errors.New(QueueUrlEnv + " is not set")
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	queueMessage := &sqs.SendMessageInput{
+		MessageBody: aws.String(record.Body),
+		QueueUrl:    aws.String(queueUrl),
+		MessageAttributes: map[string]types.MessageAttributeValue{
+			"FailureReason":   stringAttribute(cause.Error()),
+			"SourceQueue":     stringAttribute(record.EventSourceARN),
+			"SourceMessageId": stringAttribute(record.MessageId),
+		},
+	}
+	_, err = sqs.NewFromConfig(cfg).SendMessage(ctx, queueMessage)
+	if err != nil {
+		return fmt.Errorf("failed to dead-letter message %s: %w", record.MessageId, err)
+	}
+	return nil
+}
+
+// Decides what to do with a record that failed. Permanent failures are
+// dead-lettered and dropped from the batch; the rest are left for SQS to
+// redeliver. Returns true when the record should be reported as a batch
+// item failure.
+func ShouldRetry(ctx context.Context, record events.SQSMessage, cause error) bool {
+	kind := failure.Classify(cause)
+	fmt.Println(fmt.Sprintf("Message %s failed (%s): %s", record.MessageId, kind, cause))
+
+	if kind == failure.KindRetryable {
+		return true
+	}
+
+	err := Send(ctx, record, cause)
+	if err != nil {
+		fmt.Println("Error sending to dead-letter queue:", err)
+		return true
+	}
+	return false
+}
+
+func stringAttribute(value string) types.MessageAttributeValue {
+	if value == "" {
+		value = "unknown"
+	}
+	return types.MessageAttributeValue{
+		DataType:    aws.String("String"),
+		StringValue: aws.String(value),
+	}
+}
@@ -0,0 +1,122 @@
+package failure
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// Whether a failed message is worth redelivering
+type Kind int
+
+const (
+	KindRetryable Kind = iota
+	KindPermanent
+)
+
+func (k Kind) String() string {
+	if k == KindPermanent {
+		return "permanent"
+	}
+	return "retryable"
+}
+
+// MySQL error numbers for statements that are wrong for the schema or the
+// data they carry. They fail the same way on every attempt. Everything else,
+// from lock conflicts to too many connections and failovers, is transient.
+var permanentMysqlErrors = map[uint16]bool{
+	1022: true, // ER_DUP_KEY
+	1048: true, // ER_BAD_NULL_ERROR
+	1054: true, // ER_BAD_FIELD_ERROR
+	1062: true, // ER_DUP_ENTRY
+	1064: true, // ER_PARSE_ERROR
+	1136: true, // ER_WRONG_VALUE_COUNT_ON_ROW
+	1146: true, // ER_NO_SUCH_TABLE
+	1264: true, // ER_WARN_DATA_OUT_OF_RANGE
+	1265: true, // WARN_DATA_TRUNCATED
+	1292: true, // ER_TRUNCATED_WRONG_VALUE
+	1364: true, // ER_NO_DEFAULT_FOR_FIELD
+	1366: true, // ER_TRUNCATED_WRONG_VALUE_FOR_FIELD
+	1406: true, // ER_DATA_TOO_LONG
+	1451: true, // ER_ROW_IS_REFERENCED_2
+	1452: true, // ER_NO_REFERENCED_ROW_2
+	3819: true, // ER_CHECK_CONSTRAINT_VIOLATED
+}
+
+// Error carrying an explicit classification. Errors that were never wrapped
+// are classified from their cause by Classify.
+type Error struct {
+	Kind Kind
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func Retryable(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Kind: KindRetryable, Err: err}
+}
+
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Kind: KindPermanent, Err: err}
+}
+
+// Returns an error for a non 2xx response. Requests for something that does
+// not exist or was rejected as malformed will fail the same way every time.
+func CheckStatus(response *http.Response) error {
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		return nil
+	}
+
+	err := fmt.Errorf("GET %s returned %s", response.Request.URL, response.Status)
+	switch response.StatusCode {
+	case http.StatusBadRequest, http.StatusNotFound, http.StatusGone, http.StatusUnprocessableEntity:
+		return Permanent(err)
+	}
+	return Retryable(err)
+}
+
+// Classifies an error chain. An explicit classification wins. Otherwise
+// MySQL schema and data errors and undecodable payloads are permanent.
+// Anything else, other MySQL errors, network and timeout errors included, is
+// retried.
+func Classify(err error) Kind {
+	var classified *Error
+	if errors.As(err, &classified) {
+		return classified.Kind
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		if permanentMysqlErrors[mysqlErr.Number] {
+			return KindPermanent
+		}
+		return KindRetryable
+	}
+
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+		return KindPermanent
+	}
+
+	return KindRetryable
+}
+
+func IsPermanent(err error) bool {
+	return err != nil && Classify(err) == KindPermanent
+}
@@ -0,0 +1,97 @@
+package failure
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+func TestClassify(t *testing.T) {
+	syntaxErr := json.Unmarshal([]byte(`{"id":`), &struct{}{})
+	typeErr := json.Unmarshal([]byte(`{"id":"abc"}`), &struct{ Id int }{})
+
+	tests := []struct {
+		name string
+		err  error
+		want Kind
+	}{
+		{"explicitly permanent", Permanent(errors.New("bad message")), KindPermanent},
+		{"explicitly retryable", Retryable(&mysql.MySQLError{Number: 1146}), KindRetryable},
+		{"wrapped classification", fmt.Errorf("processing gamePk 1: %w", Permanent(errors.New("bad message"))), KindPermanent},
+		{"deadlock", &mysql.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock"}, KindRetryable},
+		{"lock wait timeout", &mysql.MySQLError{Number: 1205}, KindRetryable},
+		{"too many connections", &mysql.MySQLError{Number: 1040}, KindRetryable},
+		{"wrapped deadlock", fmt.Errorf("failed to insert into play_by_play: %w", &mysql.MySQLError{Number: 1213}), KindRetryable},
+		{"missing table", &mysql.MySQLError{Number: 1146}, KindPermanent},
+		{"data too long", fmt.Errorf("failed to insert into penalties: %w", &mysql.MySQLError{Number: 1406}), KindPermanent},
+		{"malformed body", fmt.Errorf("failed to decode feed: %w", syntaxErr), KindPermanent},
+		{"mistyped body", typeErr, KindPermanent},
+		{"timeout", fmt.Errorf("GET feed: %w", context.DeadlineExceeded), KindRetryable},
+		{"unclassified", errors.New("connection reset by peer"), KindRetryable},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := Classify(test.err); got != test.want {
+				t.Errorf("Classify(%v) = %s, want %s", test.err, got, test.want)
+			}
+		})
+	}
+}
+
+func TestCheckStatus(t *testing.T) {
+	tests := []struct {
+		status  int
+		wantErr bool
+		want    Kind
+	}{
+		{http.StatusOK, false, KindRetryable},
+		{http.StatusNoContent, false, KindRetryable},
+		{http.StatusBadRequest, true, KindPermanent},
+		{http.StatusNotFound, true, KindPermanent},
+		{http.StatusGone, true, KindPermanent},
+		{http.StatusUnprocessableEntity, true, KindPermanent},
+		{http.StatusForbidden, true, KindRetryable},
+		{http.StatusTooManyRequests, true, KindRetryable},
+		{http.StatusInternalServerError, true, KindRetryable},
+		{http.StatusBadGateway, true, KindRetryable},
+		{http.StatusServiceUnavailable, true, KindRetryable},
+		{http.StatusGatewayTimeout, true, KindRetryable},
+	}
+
+	requestUrl, _ := url.Parse("https://api-web.nhle.com/v1/gamecenter/2023020001/play-by-play")
+	for _, test := range tests {
+		response := &http.Response{
+			StatusCode: test.status,
+			Status:     http.StatusText(test.status),
+			Request:    &http.Request{URL: requestUrl},
+		}
+
+		err := CheckStatus(response)
+		if (err != nil) != test.wantErr {
+			t.Errorf("CheckStatus(%d) = %v, want error %t", test.status, err, test.wantErr)
+			continue
+		}
+		if err != nil && Classify(err) != test.want {
+			t.Errorf("CheckStatus(%d) classified %s, want %s", test.status, Classify(err), test.want)
+		}
+	}
+}
+
+func TestIsPermanent(t *testing.T) {
+	if IsPermanent(nil) {
+		t.Error("IsPermanent(nil) = true, want false")
+	}
+	if Permanent(nil) != nil || Retryable(nil) != nil {
+		t.Error("classifying a nil error returned an error")
+	}
+	if !IsPermanent(Permanent(errors.New("bad message"))) {
+		t.Error("IsPermanent(Permanent(err)) = false, want true")
+	}
+}
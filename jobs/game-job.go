@@ -0,0 +1,263 @@
+package jobs
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gavswe19/ice-pipelines/database"
+	"github.com/go-sql-driver/mysql"
+)
+
+const (
+	StatusInProgress = "IN_PROGRESS"
+	StatusComplete   = "COMPLETE"
+	StatusFailed     = "FAILED"
+	StatusLive       = "LIVE"
+	StatusFinal      = "FINAL"
+)
+
+// How long a run may hold a game before it is presumed dead and the game can
+// be reclaimed. Comfortably longer than the process-game Lambda timeout.
+const StaleAfter = 15 * time.Minute
+
+// Identifies the build that processed a game. Set at link time with
+// -ldflags "-X github.com/gavswe19/ice-pipelines/jobs.PipelineVersion=..."
+var PipelineVersion = "dev"
+
+// last_error is truncated to fit its column
+const maxErrorLength = 1024
+
+const mysqlDuplicateEntry = 1062
+
+// One row of etl_game_status
+type GameJob struct {
+	GamePk          int
+	Status          string
+	Attempts        int
+	LastError       string
+	StartedAt       *time.Time
+	FinishedAt      *time.Time
+	DurationMs      int64
+	PipelineVersion string
+	ContentHash     string
+}
+
+const gameJobColumns = "game_pk, status, attempts, last_error, started_at, finished_at, duration_ms, pipeline_version, content_hash"
+
+// A run that started before StaleAfter and never finished is presumed to
+// have crashed
+func (j GameJob) IsStale(now time.Time) bool {
+	return j.Status == StatusInProgress && (j.StartedAt == nil || j.StartedAt.Before(now.Add(-StaleAfter)))
+}
+
+// Returns the job for a game. A game with no row yet has an empty status.
+func GetGameJob(db *sql.DB, gamePk int) (GameJob, error) {
+	row := db.QueryRow(fmt.Sprintf("SELECT %s FROM etl_game_status WHERE game_pk = ?", gameJobColumns), gamePk)
+
+	job, err := scanGameJob(row)
+	if err == sql.ErrNoRows {
+		return GameJob{GamePk: gamePk}, nil
+	}
+	if err != nil {
+		return GameJob{}, fmt.Errorf("failed to retrieve etl game status for gamePk %d: %w", gamePk, err)
+	}
+	return job, nil
+}
+
+// Marks a game IN_PROGRESS for this run. Returns false without claiming when
+// another run holds the game and has not gone stale.
+func ClaimGameJob(db *sql.DB, gamePk int, now time.Time) (bool, error) {
+	result, err := db.Exec(`
+	UPDATE etl_game_status
+	SET status = ?, attempts = attempts + 1, last_error = NULL, started_at = ?, finished_at = NULL, duration_ms = NULL, pipeline_version = ?
+	WHERE game_pk = ? AND (status <> ? OR started_at IS NULL OR started_at < ?)`,
+		StatusInProgress, now, PipelineVersion, gamePk, StatusInProgress, now.Add(-StaleAfter))
+	if err != nil {
+		return false, fmt.Errorf("failed to claim gamePk %d: %w", gamePk, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if rowsAffected > 0 {
+		return true, nil
+	}
+
+	_, err = db.Exec(
+		"INSERT INTO etl_game_status (game_pk, status, attempts, started_at, pipeline_version) VALUES (?, ?, 1, ?, ?)",
+		gamePk, StatusInProgress, now, PipelineVersion)
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDuplicateEntry {
+		// The row exists and is held by a live run
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to claim gamePk %d: %w", gamePk, err)
+	}
+	return true, nil
+}
+
+// Marks a game COMPLETE. Pass the unit of work holding the game's rows so
+// the status commits with them.
+func CompleteGameJob(exec database.Execer, gamePk int, startedAt time.Time, contentHash string) error {
+	return finishGameJob(exec, gamePk, StatusComplete, startedAt, nil, contentHash)
+}
+
+func FailGameJob(db *sql.DB, gamePk int, startedAt time.Time, cause error) error {
+	return finishGameJob(db, gamePk, StatusFailed, startedAt, cause, "")
+}
+
+func finishGameJob(exec database.Execer, gamePk int, status string, startedAt time.Time, cause error, contentHash string) error {
+	finishedAt := time.Now().UTC()
+
+	var lastError sql.NullString
+	if cause != nil {
+		lastError = sql.NullString{String: truncate(cause.Error(), maxErrorLength), Valid: true}
+	}
+
+	var hash sql.NullString
+	if contentHash != "" {
+		hash = sql.NullString{String: contentHash, Valid: true}
+	}
+
+	_, err := exec.Exec(`
+	UPDATE etl_game_status
+	SET status = ?, last_error = ?, finished_at = ?, duration_ms = ?, content_hash = COALESCE(?, content_hash)
+	WHERE game_pk = ?`,
+		status, lastError, finishedAt, finishedAt.Sub(startedAt).Milliseconds(), hash, gamePk)
+	if err != nil {
+		return fmt.Errorf("failed to update etl_game_status for gamePk %d: %w", gamePk, err)
+	}
+
+	println(fmt.Sprintf("Marked gamePk %d %s", gamePk, status))
+	return nil
+}
+
+// Moves a game to status to when its status is one of from, where "" stands
+// for a game with no row yet. Returns false, leaving the game alone, when it
+// is in any other status, e.g. because process-game has claimed it since.
+// Run in a unit of work, the row stays locked until the work commits.
+func TransitionGameJobStatus(q database.Queryer, gamePk int, from []string, to string) (bool, error) {
+	status, err := lockGameJobStatus(q, gamePk)
+	if err != nil {
+		return false, err
+	}
+	if !slices.Contains(from, status) {
+		return false, nil
+	}
+	if status == to {
+		return true, nil
+	}
+
+	if status == "" {
+		_, err := q.Exec("INSERT INTO etl_game_status (game_pk, status) VALUES (?, ?)", gamePk, to)
+
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDuplicateEntry {
+			// Another run created the row first
+			return false, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("failed to update etl_game_status for gamePk %d: %w", gamePk, err)
+		}
+		return true, nil
+	}
+
+	// Outside a unit of work the row is not locked, so the update only
+	// applies if the status is still the one read
+	result, err := q.Exec("UPDATE etl_game_status SET status = ? WHERE game_pk = ? AND status = ?", to, gamePk, status)
+	if err != nil {
+		return false, fmt.Errorf("failed to update etl_game_status for gamePk %d: %w", gamePk, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+// Reads a game's status with a row lock. A game with no row yet has an empty
+// status.
+func lockGameJobStatus(q database.Queryer, gamePk int) (string, error) {
+	rows, err := q.Query("SELECT status FROM etl_game_status WHERE game_pk = ? FOR UPDATE", gamePk)
+	if err != nil {
+		return "", fmt.Errorf("failed to retrieve etl game status for gamePk %d: %w", gamePk, err)
+	}
+	defer rows.Close()
+
+	var status string
+	if rows.Next() {
+		if err := rows.Scan(&status); err != nil {
+			return "", fmt.Errorf("failed to retrieve etl game status for gamePk %d: %w", gamePk, err)
+		}
+	}
+	return status, rows.Err()
+}
+
+// Returns games whose last run failed and games stuck IN_PROGRESS past
+// StaleAfter
+func ListUnfinishedGameJobs(db *sql.DB, now time.Time) ([]GameJob, error) {
+	results, err := db.Query(fmt.Sprintf(`
+	SELECT %s
+	FROM etl_game_status
+	WHERE status = ? OR (status = ? AND (started_at IS NULL OR started_at < ?))
+	ORDER BY game_pk`, gameJobColumns),
+		StatusFailed, StatusInProgress, now.Add(-StaleAfter))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list unfinished games: %w", err)
+	}
+	defer results.Close()
+
+	jobs := []GameJob{}
+	for results.Next() {
+		job, err := scanGameJob(results)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, results.Err()
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanGameJob(row scanner) (GameJob, error) {
+	var job GameJob
+	var lastError, pipelineVersion, contentHash sql.NullString
+	var startedAt, finishedAt sql.NullTime
+	var durationMs sql.NullInt64
+
+	err := row.Scan(&job.GamePk, &job.Status, &job.Attempts, &lastError, &startedAt, &finishedAt, &durationMs, &pipelineVersion, &contentHash)
+	if err != nil {
+		return GameJob{}, err
+	}
+
+	job.LastError = lastError.String
+	job.DurationMs = durationMs.Int64
+	job.PipelineVersion = pipelineVersion.String
+	job.ContentHash = contentHash.String
+	if startedAt.Valid {
+		job.StartedAt = &startedAt.Time
+	}
+	if finishedAt.Valid {
+		job.FinishedAt = &finishedAt.Time
+	}
+	return job, nil
+}
+
+func truncate(value string, length int) string {
+	value = strings.ToValidUTF8(value, "")
+	if len(value) <= length {
+		return value
+	}
+	return value[:length]
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/gavswe19/ice-pipelines/aggregates"
+	"github.com/gavswe19/ice-pipelines/database"
+)
+
+// Rebuilds team_season_line_performance for a season, or with -player lists
+// the lines that player skated on ordered by GF%
+func main() {
+	season := flag.String("season", "20232024", "season to aggregate, e.g. 20232024")
+	playerId := flag.Int("player", 0, "list lines containing this player instead of rebuilding")
+	flag.Parse()
+
+	db, err := database.GetDatabase()
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+
+	if *playerId == 0 {
+		if err := aggregates.BuildLineSeasonPerformance(db, *season); err != nil {
+			log.Fatalf("Failed to build line season performance: %v", err)
+		}
+		return
+	}
+
+	lines, err := aggregates.LinesForPlayer(db, *season, *playerId)
+	if err != nil {
+		log.Fatalf("Failed to fetch lines: %v", err)
+	}
+
+	for _, line := range lines {
+		fmt.Printf("%d %s events=%d CF=%d CA=%d GF=%d GA=%d GF%%=%.3f TOI=%ds\n",
+			line.TeamId, line.LineHash, line.Events, line.CF, line.CA, line.GF, line.GA, line.GoalsForPct(), line.ToiSeconds)
+	}
+}
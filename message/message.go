@@ -0,0 +1,77 @@
+package message
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version of the envelope written by this package. Bodies without a version
+// are legacy bare integer messages.
+const SchemaVersion = 1
+
+const (
+	EntityGame               = "game"
+	EntityPlayer             = "player"
+	EntityPlayerSeasonTotals = "player_season_totals"
+)
+
+// JSON body of every message sent to the pipeline queues
+type Envelope struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	EntityType    string `json:"entityType"`
+	Id            int    `json:"id"`
+	Season        int    `json:"season,omitempty"`
+	Force         bool   `json:"force,omitempty"`
+	RequestedBy   string `json:"requestedBy,omitempty"`
+}
+
+func New(entityType string, id int, requestedBy string) Envelope {
+	return Envelope{
+		SchemaVersion: SchemaVersion,
+		EntityType:    entityType,
+		Id:            id,
+		RequestedBy:   requestedBy,
+	}
+}
+
+func (e Envelope) WithSeason(season int) Envelope {
+	e.Season = season
+	return e
+}
+
+func (e Envelope) Encode() (string, error) {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode %s message for id %d: %w", e.EntityType, e.Id, err)
+	}
+	return string(body), nil
+}
+
+// Parses a queue message body into an envelope for the expected entity type.
+// Legacy bodies holding only the id are accepted as schema version 0.
+func Parse(body string, entityType string) (Envelope, error) {
+	body = strings.TrimSpace(body)
+
+	if id, err := strconv.Atoi(body); err == nil {
+		return Envelope{EntityType: entityType, Id: id}, nil
+	}
+
+	var envelope Envelope
+	if err := json.Unmarshal([]byte(body), &envelope); err != nil {
+		return envelope, fmt.Errorf("malformed message body %q: %w", body, err)
+	}
+
+	if envelope.SchemaVersion < 1 || envelope.SchemaVersion > SchemaVersion {
+		return envelope, fmt.Errorf("unsupported message schema version %d", envelope.SchemaVersion)
+	}
+	if envelope.EntityType != entityType {
+		return envelope, fmt.Errorf("expected %s message, got %s", entityType, envelope.EntityType)
+	}
+	if envelope.Id == 0 {
+		return envelope, fmt.Errorf("%s message is missing an id", entityType)
+	}
+
+	return envelope, nil
+}
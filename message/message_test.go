@@ -0,0 +1,85 @@
+package message
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseLegacyBody(t *testing.T) {
+	for _, body := range []string{"12345", " 12345\n"} {
+		envelope, err := Parse(body, EntityGame)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", body, err)
+		}
+
+		want := Envelope{EntityType: EntityGame, Id: 12345}
+		if envelope != want {
+			t.Errorf("Parse(%q) = %+v, want %+v", body, envelope, want)
+		}
+	}
+}
+
+func TestParseEnvelope(t *testing.T) {
+	body := `{"schemaVersion":1,"entityType":"player_season_totals","id":8478402,"season":20232024,"force":true,"requestedBy":"requeue"}`
+
+	envelope, err := Parse(body, EntityPlayerSeasonTotals)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	want := Envelope{
+		SchemaVersion: 1,
+		EntityType:    EntityPlayerSeasonTotals,
+		Id:            8478402,
+		Season:        20232024,
+		Force:         true,
+		RequestedBy:   "requeue",
+	}
+	if envelope != want {
+		t.Errorf("Parse = %+v, want %+v", envelope, want)
+	}
+}
+
+func TestParseEncodedEnvelope(t *testing.T) {
+	sent := New(EntityPlayer, 8478402, "populate-player-queue").WithSeason(20232024)
+	body, err := sent.Encode()
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+
+	received, err := Parse(body, EntityPlayer)
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", body, err)
+	}
+	if received != sent {
+		t.Errorf("Parse(Encode()) = %+v, want %+v", received, sent)
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr string
+	}{
+		{"entity mismatch", `{"schemaVersion":1,"entityType":"player","id":8478402}`, "expected game message, got player"},
+		{"malformed json", `{"schemaVersion":1,"entityType":"game"`, "malformed message body"},
+		{"not a number or json", "2023020001a", "malformed message body"},
+		{"empty body", "", "malformed message body"},
+		{"missing schema version", `{"entityType":"game","id":2023020001}`, "unsupported message schema version 0"},
+		{"future schema version", `{"schemaVersion":2,"entityType":"game","id":2023020001}`, "unsupported message schema version 2"},
+		{"missing id", `{"schemaVersion":1,"entityType":"game"}`, "missing an id"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Parse(test.body, EntityGame)
+			if err == nil {
+				t.Fatalf("Parse(%q) succeeded, want an error", test.body)
+			}
+			if !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("Parse(%q) error = %q, want it to contain %q", test.body, err, test.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package model
+
+// Response shape of api-web.nhle.com/v1/gamecenter/{gamePk}/boxscore
+type BoxscoreResponse struct {
+	Id                int               `json:"id"`
+	AwayTeam          GamecenterTeam    `json:"awayTeam"`
+	HomeTeam          GamecenterTeam    `json:"homeTeam"`
+	PlayerByGameStats PlayerByGameStats `json:"playerByGameStats"`
+}
+
+type PlayerByGameStats struct {
+	AwayTeam TeamPlayerStats `json:"awayTeam"`
+	HomeTeam TeamPlayerStats `json:"homeTeam"`
+}
+
+type TeamPlayerStats struct {
+	Forwards []SkaterStats `json:"forwards"`
+	Defense  []SkaterStats `json:"defense"`
+	Goalies  []GoalieStats `json:"goalies"`
+}
+
+type SkaterStats struct {
+	PlayerId     int    `json:"playerId"`
+	Position     string `json:"position"`
+	Goals        int    `json:"goals"`
+	Assists      int    `json:"assists"`
+	Points       int    `json:"points"`
+	PlusMinus    int    `json:"plusMinus"`
+	Pim          int    `json:"pim"`
+	Hits         int    `json:"hits"`
+	Sog          int    `json:"sog"`
+	BlockedShots int    `json:"blockedShots"`
+	Shifts       int    `json:"shifts"`
+	Giveaways    int    `json:"giveaways"`
+	Takeaways    int    `json:"takeaways"`
+	Toi          string `json:"toi"`
+}
+
+type GoalieStats struct {
+	PlayerId     int    `json:"playerId"`
+	Position     string `json:"position"`
+	Pim          int    `json:"pim"`
+	Toi          string `json:"toi"`
+	ShotsAgainst int    `json:"shotsAgainst"`
+	Saves        int    `json:"saves"`
+	GoalsAgainst int    `json:"goalsAgainst"`
+	Decision     string `json:"decision"`
+	Starter      bool   `json:"starter"`
+}
@@ -0,0 +1,32 @@
+package model
+
+// Response shape of api-web.nhle.com/v1/club-stats/{team}/{season}/{gameType}
+type ClubStatsResponse struct {
+	Season   string            `json:"season"`
+	GameType int               `json:"gameType"`
+	Skaters  []ClubStatsPlayer `json:"skaters"`
+	Goalies  []ClubStatsPlayer `json:"goalies"`
+}
+
+type ClubStatsPlayer struct {
+	PlayerId     int         `json:"playerId"`
+	Headshot     string      `json:"headshot"`
+	FirstName    Translation `json:"firstName"`
+	LastName     Translation `json:"lastName"`
+	PositionCode string      `json:"positionCode"`
+}
+
+// Skaters then goalies
+func (r ClubStatsResponse) ToPlayers() []Player {
+	players := make([]Player, 0, len(r.Skaters)+len(r.Goalies))
+	for _, player := range append(append([]ClubStatsPlayer{}, r.Skaters...), r.Goalies...) {
+		players = append(players, Player{
+			Id:        player.PlayerId,
+			FirstName: player.FirstName.Default,
+			LastName:  player.LastName.Default,
+			Position:  player.PositionCode,
+			Headshot:  player.Headshot,
+		})
+	}
+	return players
+}
@@ -0,0 +1,12 @@
+package model
+
+type Game struct {
+	GamePk int
+	Season Season
+	// statsapi game type code: PR, R, P or A
+	GameType string
+	// UTC start time as the feed wrote it
+	StartTime  string
+	AwayTeamId int
+	HomeTeamId int
+}
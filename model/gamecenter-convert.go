@@ -0,0 +1,212 @@
+package model
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+type legacyEventType struct {
+	event       string
+	eventTypeId string
+}
+
+// Maps gamecenter typeDescKey values to the event / eventTypeId pairs the
+// statsapi feed used, so queries against play_by_play work for both eras.
+var gamecenterEventTypes = map[string]legacyEventType{
+	"faceoff":             {"Faceoff", "FACEOFF"},
+	"hit":                 {"Hit", "HIT"},
+	"giveaway":            {"Giveaway", "GIVEAWAY"},
+	"takeaway":            {"Takeaway", "TAKEAWAY"},
+	"shot-on-goal":        {"Shot", "SHOT"},
+	"missed-shot":         {"Missed Shot", "MISSED_SHOT"},
+	"blocked-shot":        {"Blocked Shot", "BLOCKED_SHOT"},
+	"goal":                {"Goal", "GOAL"},
+	"penalty":             {"Penalty", "PENALTY"},
+	"stoppage":            {"Stoppage", "STOP"},
+	"period-start":        {"Period Start", "PERIOD_START"},
+	"period-end":          {"Period End", "PERIOD_END"},
+	"game-end":            {"Game End", "GAME_END"},
+	"shootout-complete":   {"Shootout Complete", "SHOOTOUT_COMPLETE"},
+	"failed-shot-attempt": {"Failed Shot Attempt", "FAILED_SHOT_ATTEMPT"},
+	"delayed-penalty":     {"Delayed Penalty", "DELAYED_PENALTY"},
+}
+
+var gamecenterPeriodTypes = map[string]string{
+	"REG": "REGULAR",
+	"OT":  "OVERTIME",
+	"SO":  "SHOOTOUT",
+}
+
+var gamecenterPenaltySeverities = map[string]string{
+	"MIN":  "Minor",
+	"BEN":  "Bench Minor",
+	"MAJ":  "Major",
+	"MIS":  "Misconduct",
+	"GMIS": "Game Misconduct",
+	"MAT":  "Match",
+	"PS":   "Penalty Shot",
+}
+
+var gamecenterGameTypes = map[int]string{
+	1: "PR",
+	2: "R",
+	3: "P",
+	4: "A",
+}
+
+func (game GamecenterResponse) ToGame() Game {
+	return Game{
+		GamePk:     game.Id,
+		Season:     Season(game.Season),
+		GameType:   gamecenterGameTypes[game.GameType],
+		StartTime:  game.StartTimeUTC,
+		AwayTeamId: game.AwayTeam.Id,
+		HomeTeamId: game.HomeTeam.Id,
+	}
+}
+
+// EventIdx is the play's sortOrder rather than its position. Plays are
+// inserted and removed while a game is live, which shifts positions, but a
+// play keeps its sortOrder. Plays are returned in sortOrder so every table
+// keyed on event_idx, and anything built from play order, agrees with it.
+func (game GamecenterResponse) ToPlays() []Play {
+	gcPlays := slices.Clone(game.Plays)
+	slices.SortStableFunc(gcPlays, func(a, b GamecenterPlay) int {
+		return a.SortOrder - b.SortOrder
+	})
+
+	plays := make([]Play, 0, len(gcPlays))
+	goals := Goals{}
+
+	for _, gcPlay := range gcPlays {
+		details := gcPlay.Details
+
+		if gcPlay.TypeDescKey == "goal" {
+			goals = Goals{Away: details.AwayScore, Home: details.HomeScore}
+		}
+
+		eventType, ok := gamecenterEventTypes[gcPlay.TypeDescKey]
+		if !ok {
+			eventType = legacyEventType{
+				event:       gcPlay.TypeDescKey,
+				eventTypeId: strings.ToUpper(strings.ReplaceAll(gcPlay.TypeDescKey, "-", "_")),
+			}
+		}
+
+		result := Result{
+			Event:         eventType.event,
+			EventCode:     strconv.Itoa(gcPlay.TypeCode),
+			EventTypeId:   eventType.eventTypeId,
+			SecondaryType: details.ShotType,
+		}
+		if gcPlay.TypeDescKey == "penalty" {
+			result.SecondaryType = details.DescKey
+			result.PenaltySeverity = gamecenterPenaltySeverities[details.TypeCode]
+			result.PenaltyMinutes = details.Duration
+		}
+
+		plays = append(plays, Play{
+			Players: gamecenterContributors(gcPlay),
+			Result:  result,
+			About: About{
+				EventIdx:            gcPlay.SortOrder,
+				EvendId:             gcPlay.EventId,
+				Period:              gcPlay.PeriodDescriptor.Number,
+				PeriodType:          gamecenterPeriodTypes[gcPlay.PeriodDescriptor.PeriodType],
+				OrdinalNum:          ordinalPeriod(gcPlay.PeriodDescriptor),
+				PeriodTime:          gcPlay.TimeInPeriod,
+				PeriodTimeRemaining: gcPlay.TimeRemaining,
+				Goals:               goals,
+			},
+			Coordinates:           Coordinates{X: details.XCoord, Y: details.YCoord},
+			Team:                  TeamRef{Id: details.EventOwnerTeamId},
+			SituationCode:         gcPlay.SituationCode,
+			HomeTeamDefendingSide: gcPlay.HomeTeamDefendingSide,
+		})
+	}
+
+	return plays
+}
+
+// Ids of every goalie dressed by either team
+func (game GamecenterResponse) Goalies() []int {
+	var goalies []int
+	for _, rosterSpot := range game.RosterSpots {
+		if rosterSpot.PositionCode == "G" {
+			goalies = append(goalies, rosterSpot.PlayerId)
+		}
+	}
+	return goalies
+}
+
+// Position codes of every dressed player by player id
+func (game GamecenterResponse) Positions() map[int]string {
+	positions := make(map[int]string, len(game.RosterSpots))
+	for _, rosterSpot := range game.RosterSpots {
+		positions[rosterSpot.PlayerId] = rosterSpot.PositionCode
+	}
+	return positions
+}
+
+// Player roles use the statsapi playerType names so play_by_play_contributor
+// stays consistent across feeds.
+func gamecenterContributors(gcPlay GamecenterPlay) []Contributor {
+	details := gcPlay.Details
+	var players []Contributor
+
+	add := func(playerId int, playerType string) {
+		if playerId == 0 {
+			return
+		}
+		players = append(players, Contributor{Player: PlayerRef{PlayerId: playerId}, PlayerType: playerType})
+	}
+
+	switch gcPlay.TypeDescKey {
+	case "faceoff":
+		add(details.WinningPlayerId, "Winner")
+		add(details.LosingPlayerId, "Loser")
+	case "hit":
+		add(details.HittingPlayerId, "Hitter")
+		add(details.HitteePlayerId, "Hittee")
+	case "giveaway", "takeaway":
+		add(details.PlayerId, "PlayerID")
+	case "shot-on-goal", "missed-shot", "failed-shot-attempt":
+		add(details.ShootingPlayerId, "Shooter")
+		add(details.GoalieInNetId, "Goalie")
+	case "blocked-shot":
+		add(details.BlockingPlayerId, "Blocker")
+		add(details.ShootingPlayerId, "Shooter")
+	case "goal":
+		add(details.ScoringPlayerId, "Scorer")
+		add(details.Assist1PlayerId, "Assist")
+		add(details.Assist2PlayerId, "Assist")
+		add(details.GoalieInNetId, "Goalie")
+	case "penalty":
+		add(details.CommittedByPlayerId, "PenaltyOn")
+		add(details.DrawnByPlayerId, "DrewBy")
+		add(details.ServedByPlayerId, "ServedBy")
+	}
+
+	return players
+}
+
+func ordinalPeriod(period PeriodDescriptor) string {
+	switch period.PeriodType {
+	case "SO":
+		return "SO"
+	case "OT":
+		if period.Number == 4 {
+			return "OT"
+		}
+		return strconv.Itoa(period.Number-3) + "OT"
+	}
+
+	switch period.Number {
+	case 1:
+		return "1st"
+	case 2:
+		return "2nd"
+	}
+	return "3rd"
+}
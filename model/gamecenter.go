@@ -0,0 +1,78 @@
+package model
+
+// Response shape of api-web.nhle.com/v1/gamecenter/{gamePk}/play-by-play.
+// Plays from this feed are converted to the statsapi Play model so both eras
+// land in the same tables.
+type GamecenterResponse struct {
+	Id           int              `json:"id"`
+	Season       int              `json:"season"`
+	GameType     int              `json:"gameType"`
+	GameDate     string           `json:"gameDate"`
+	StartTimeUTC string           `json:"startTimeUTC"`
+	GameState    string           `json:"gameState"`
+	AwayTeam     GamecenterTeam   `json:"awayTeam"`
+	HomeTeam     GamecenterTeam   `json:"homeTeam"`
+	Plays        []GamecenterPlay `json:"plays"`
+	RosterSpots  []RosterSpot     `json:"rosterSpots"`
+}
+
+type GamecenterTeam struct {
+	Id     int    `json:"id"`
+	Abbrev string `json:"abbrev"`
+	Score  int    `json:"score"`
+}
+
+type GamecenterPlay struct {
+	EventId               int              `json:"eventId"`
+	PeriodDescriptor      PeriodDescriptor `json:"periodDescriptor"`
+	TimeInPeriod          string           `json:"timeInPeriod"`
+	TimeRemaining         string           `json:"timeRemaining"`
+	SituationCode         string           `json:"situationCode"`
+	HomeTeamDefendingSide string           `json:"homeTeamDefendingSide"`
+	TypeCode              int              `json:"typeCode"`
+	TypeDescKey           string           `json:"typeDescKey"`
+	SortOrder             int              `json:"sortOrder"`
+	Details               PlayDetails      `json:"details"`
+}
+
+type PeriodDescriptor struct {
+	Number     int    `json:"number"`
+	PeriodType string `json:"periodType"`
+}
+
+type PlayDetails struct {
+	EventOwnerTeamId    int     `json:"eventOwnerTeamId"`
+	XCoord              float32 `json:"xCoord"`
+	YCoord              float32 `json:"yCoord"`
+	ZoneCode            string  `json:"zoneCode"`
+	ShotType            string  `json:"shotType"`
+	Reason              string  `json:"reason"`
+	ShootingPlayerId    int     `json:"shootingPlayerId"`
+	GoalieInNetId       int     `json:"goalieInNetId"`
+	ScoringPlayerId     int     `json:"scoringPlayerId"`
+	Assist1PlayerId     int     `json:"assist1PlayerId"`
+	Assist2PlayerId     int     `json:"assist2PlayerId"`
+	AwayScore           int     `json:"awayScore"`
+	HomeScore           int     `json:"homeScore"`
+	AwaySOG             int     `json:"awaySOG"`
+	HomeSOG             int     `json:"homeSOG"`
+	WinningPlayerId     int     `json:"winningPlayerId"`
+	LosingPlayerId      int     `json:"losingPlayerId"`
+	HittingPlayerId     int     `json:"hittingPlayerId"`
+	HitteePlayerId      int     `json:"hitteePlayerId"`
+	BlockingPlayerId    int     `json:"blockingPlayerId"`
+	PlayerId            int     `json:"playerId"`
+	TypeCode            string  `json:"typeCode"`
+	DescKey             string  `json:"descKey"`
+	Duration            int     `json:"duration"`
+	CommittedByPlayerId int     `json:"committedByPlayerId"`
+	DrawnByPlayerId     int     `json:"drawnByPlayerId"`
+	ServedByPlayerId    int     `json:"servedByPlayerId"`
+}
+
+type RosterSpot struct {
+	TeamId        int    `json:"teamId"`
+	PlayerId      int    `json:"playerId"`
+	SweaterNumber int    `json:"sweaterNumber"`
+	PositionCode  string `json:"positionCode"`
+}
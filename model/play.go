@@ -0,0 +1,72 @@
+package model
+
+import (
+	"strconv"
+	"strings"
+)
+
+// A play in the statsapi shape. Gamecenter plays are converted to it so both
+// eras land in the same tables.
+type Play struct {
+	Players     []Contributor `json:"players"`
+	Result      Result        `json:"result"`
+	About       About         `json:"about"`
+	Coordinates Coordinates   `json:"coordinates"`
+	Team        TeamRef       `json:"team"`
+
+	// Only the gamecenter feed carries these
+	SituationCode         string `json:"-"`
+	HomeTeamDefendingSide string `json:"-"`
+}
+
+// A player involved in a play and their role, e.g. Shooter or Goalie
+type Contributor struct {
+	Player     PlayerRef `json:"player"`
+	PlayerType string    `json:"playerType"`
+}
+
+type Result struct {
+	Event           string `json:"event"`
+	EventCode       string `json:"eventCode"`
+	EventTypeId     string `json:"eventTypeId"`
+	Description     string `json:"description"`
+	SecondaryType   string `json:"secondaryType"`
+	PenaltySeverity string `json:"penaltySeverity"`
+	PenaltyMinutes  int    `json:"penaltyMinutes"`
+}
+
+type About struct {
+	EventIdx            int    `json:"eventIdx"`
+	EvendId             int    `json:"eventId"`
+	Period              int    `json:"period"`
+	PeriodType          string `json:"periodType"`
+	OrdinalNum          string `json:"ordinalNum"`
+	PeriodTime          string `json:"periodTime"`
+	PeriodTimeRemaining string `json:"periodTimeRemaining"`
+	DateTime            string `json:"dateTime"`
+	Goals               Goals  `json:"goals"`
+}
+
+type Coordinates struct {
+	X float32 `json:"x"`
+	Y float32 `json:"y"`
+}
+
+// Score after the play
+type Goals struct {
+	Away int `json:"away"`
+	Home int `json:"home"`
+}
+
+// Converts an "MM:SS" clock, such as a period time, shift start or time on
+// ice, to elapsed seconds
+func PeriodTimeToSeconds(periodTime string) int {
+	parts := strings.Split(periodTime, ":")
+	if len(parts) != 2 {
+		return 0
+	}
+
+	minutes, _ := strconv.Atoi(parts[0])
+	seconds, _ := strconv.Atoi(parts[1])
+	return minutes*60 + seconds
+}
@@ -0,0 +1,80 @@
+package model
+
+// Response shape of api-web.nhle.com/v1/player/{playerId}/landing
+type PlayerLandingResponse struct {
+	PlayerId            int            `json:"playerId"`
+	IsActive            bool           `json:"isActive"`
+	CurrentTeamId       int            `json:"currentTeamId"`
+	CurrentTeamAbbrev   string         `json:"currentTeamAbbrev"`
+	FullTeamName        Translation    `json:"fullTeamName"`
+	FirstName           Translation    `json:"firstName"`
+	LastName            Translation    `json:"lastName"`
+	TeamLogo            string         `json:"teamLogo"`
+	SweaterNumber       int            `json:"sweaterNumber"`
+	Position            string         `json:"position"`
+	Headshot            string         `json:"headshot"`
+	HeroImage           string         `json:"heroImage"`
+	HeightInInches      int            `json:"heightInInches"`
+	HeightInCentimeters int            `json:"heightInCentimeters"`
+	WeightInPounds      int            `json:"weightInPounds"`
+	WeightInKilograms   int            `json:"weightInKilograms"`
+	BirthDate           string         `json:"birthDate"`
+	BirthCity           Translation    `json:"birthCity"`
+	BirthStateProvince  Translation    `json:"birthStateProvince"`
+	BirthCountry        string         `json:"birthCountry"`
+	ShootsCatches       string         `json:"shootsCatches"`
+	DraftDetails        Draft          `json:"draftDetails"`
+	SeasonTotals        []SeasonTotals `json:"seasonTotals"`
+}
+
+// One line of a player's career, per season, league, team and game type.
+// Stats a league does not track are nil.
+type SeasonTotals struct {
+	Season             int      `json:"season"`
+	GameTypeId         int      `json:"gameTypeId"`
+	LeagueAbbrev       string   `json:"leagueAbbrev"`
+	TeamName           string   `json:"teamName"`
+	Sequence           int      `json:"sequence"`
+	GamesPlayed        *int     `json:"gamesPlayed"`
+	Goals              *int     `json:"goals"`
+	Assists            *int     `json:"assists"`
+	Points             *int     `json:"points"`
+	PlusMinus          *int     `json:"plusMinus"`
+	PowerPlayGoals     *int     `json:"powerPlayGoals"`
+	PowerPlayPoints    *int     `json:"powerPlayPoints"`
+	ShorthandedPoints  *int     `json:"shorthandedPoints"`
+	GameWinningGoals   *int     `json:"gameWinningGoals"`
+	OtGoals            *int     `json:"otGoals"`
+	Shots              *int64   `json:"shots"`
+	ShootingPctg       *float64 `json:"shootingPctg"`
+	FaceoffWinningPctg *float64 `json:"faceoffWinningPctg"`
+	AvgToi             *string  `json:"avgToi"`
+	ShorthandedGoals   *int     `json:"shorthandedGoals"`
+	Pim                *int     `json:"pim"`
+}
+
+func (r PlayerLandingResponse) ToPlayer() Player {
+	return Player{
+		Id:                  r.PlayerId,
+		FirstName:           r.FirstName.Default,
+		LastName:            r.LastName.Default,
+		Position:            r.Position,
+		SweaterNumber:       r.SweaterNumber,
+		ShootsCatches:       r.ShootsCatches,
+		IsActive:            r.IsActive,
+		CurrentTeamId:       r.CurrentTeamId,
+		CurrentTeamAbbrev:   r.CurrentTeamAbbrev,
+		CurrentTeamName:     r.FullTeamName.Default,
+		Headshot:            r.Headshot,
+		HeroImage:           r.HeroImage,
+		HeightInInches:      r.HeightInInches,
+		HeightInCentimeters: r.HeightInCentimeters,
+		WeightInPounds:      r.WeightInPounds,
+		WeightInKilograms:   r.WeightInKilograms,
+		BirthDate:           r.BirthDate,
+		BirthCity:           r.BirthCity.Default,
+		BirthStateProvince:  r.BirthStateProvince.Default,
+		BirthCountry:        r.BirthCountry,
+		Draft:               r.DraftDetails,
+	}
+}
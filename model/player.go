@@ -0,0 +1,45 @@
+package model
+
+// A player's biography. Sources other than the player landing fill in only
+// what they carry.
+type Player struct {
+	Id                  int
+	FirstName           string
+	LastName            string
+	Position            string
+	SweaterNumber       int
+	ShootsCatches       string
+	IsActive            bool
+	CurrentTeamId       int
+	CurrentTeamAbbrev   string
+	CurrentTeamName     string
+	Headshot            string
+	HeroImage           string
+	HeightInInches      int
+	HeightInCentimeters int
+	WeightInPounds      int
+	WeightInKilograms   int
+	BirthDate           string
+	BirthCity           string
+	BirthStateProvince  string
+	BirthCountry        string
+	Draft               Draft
+}
+
+func (p Player) FullName() string {
+	return p.FirstName + " " + p.LastName
+}
+
+// Zero for undrafted players
+type Draft struct {
+	Year        int    `json:"year"`
+	TeamAbbrev  string `json:"teamAbbrev"`
+	Round       int    `json:"round"`
+	PickInRound int    `json:"pickInRound"`
+	OverallPick int    `json:"overallPick"`
+}
+
+// A reference to a player by id, as the statsapi feed embeds them in plays
+type PlayerRef struct {
+	PlayerId int `json:"id"`
+}
@@ -0,0 +1,52 @@
+package model
+
+// Response shape of api-web.nhle.com/v1/roster/{team}/{season}
+type RosterResponse struct {
+	Forwards   []RosterPlayer `json:"forwards"`
+	Defensemen []RosterPlayer `json:"defensemen"`
+	Goalies    []RosterPlayer `json:"goalies"`
+}
+
+type RosterPlayer struct {
+	Id                  int         `json:"id"`
+	Headshot            string      `json:"headshot"`
+	FirstName           Translation `json:"firstName"`
+	LastName            Translation `json:"lastName"`
+	SweaterNumber       int         `json:"sweaterNumber"`
+	PositionCode        string      `json:"positionCode"`
+	ShootsCatches       string      `json:"shootsCatches"`
+	HeightInInches      int         `json:"heightInInches"`
+	WeightInPounds      int         `json:"weightInPounds"`
+	HeightInCentimeters int         `json:"heightInCentimeters"`
+	WeightInKilograms   int         `json:"weightInKilograms"`
+	BirthDate           string      `json:"birthDate"`
+	BirthCity           Translation `json:"birthCity"`
+	BirthCountry        string      `json:"birthCountry"`
+	BirthStateProvince  Translation `json:"birthStateProvince"`
+}
+
+func (r RosterResponse) ToPlayers() []Player {
+	rosterPlayers := append(append(append([]RosterPlayer{}, r.Forwards...), r.Defensemen...), r.Goalies...)
+
+	players := make([]Player, 0, len(rosterPlayers))
+	for _, player := range rosterPlayers {
+		players = append(players, Player{
+			Id:                  player.Id,
+			FirstName:           player.FirstName.Default,
+			LastName:            player.LastName.Default,
+			Position:            player.PositionCode,
+			SweaterNumber:       player.SweaterNumber,
+			ShootsCatches:       player.ShootsCatches,
+			Headshot:            player.Headshot,
+			HeightInInches:      player.HeightInInches,
+			HeightInCentimeters: player.HeightInCentimeters,
+			WeightInPounds:      player.WeightInPounds,
+			WeightInKilograms:   player.WeightInKilograms,
+			BirthDate:           player.BirthDate,
+			BirthCity:           player.BirthCity.Default,
+			BirthStateProvince:  player.BirthStateProvince.Default,
+			BirthCountry:        player.BirthCountry,
+		})
+	}
+	return players
+}
@@ -0,0 +1,49 @@
+package model
+
+// Response shape of api-web.nhle.com/v1/schedule/{date}. The response covers
+// the week starting at date.
+type ScheduleResponse struct {
+	GameWeek []ScheduleDay `json:"gameWeek"`
+}
+
+type ScheduleDay struct {
+	Date  string         `json:"date"`
+	Games []ScheduleGame `json:"games"`
+}
+
+type ScheduleGame struct {
+	Id           int          `json:"id"`
+	Season       int          `json:"season"`
+	GameType     int          `json:"gameType"`
+	StartTimeUTC string       `json:"startTimeUTC"`
+	GameState    string       `json:"gameState"`
+	AwayTeam     ScheduleTeam `json:"awayTeam"`
+	HomeTeam     ScheduleTeam `json:"homeTeam"`
+}
+
+type ScheduleTeam struct {
+	Id         int         `json:"id"`
+	Abbrev     string      `json:"abbrev"`
+	CommonName Translation `json:"commonName"`
+}
+
+// Games scheduled on date, formatted 2006-01-02
+func (r ScheduleResponse) ToGames(date string) []Game {
+	games := []Game{}
+	for _, day := range r.GameWeek {
+		if day.Date != date {
+			continue
+		}
+		for _, game := range day.Games {
+			games = append(games, Game{
+				GamePk:     game.Id,
+				Season:     Season(game.Season),
+				GameType:   gamecenterGameTypes[game.GameType],
+				StartTime:  game.StartTimeUTC,
+				AwayTeamId: game.AwayTeam.Id,
+				HomeTeamId: game.HomeTeam.Id,
+			})
+		}
+	}
+	return games
+}
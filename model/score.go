@@ -0,0 +1,12 @@
+package model
+
+// Response shape of api-web.nhle.com/v1/score/now
+type ScoreResponse struct {
+	CurrentDate string      `json:"currentDate"`
+	Games       []ScoreGame `json:"games"`
+}
+
+type ScoreGame struct {
+	Id        int    `json:"id"`
+	GameState string `json:"gameState"`
+}
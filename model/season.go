@@ -0,0 +1,35 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// A season as the APIs write it, e.g. 20232024
+type Season int
+
+func ParseSeason(value string) (Season, error) {
+	season, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid season %q: %w", value, err)
+	}
+	return Season(season), nil
+}
+
+// Game ids begin with the year their season started, e.g. 2023020001
+func SeasonOfGame(gamePk int) Season {
+	startYear := gamePk / 1000000
+	return Season(startYear*10000 + startYear + 1)
+}
+
+func (s Season) StartYear() int {
+	return int(s) / 10000
+}
+
+func (s Season) EndYear() int {
+	return int(s) % 10000
+}
+
+func (s Season) String() string {
+	return strconv.Itoa(int(s))
+}
@@ -0,0 +1,36 @@
+package model
+
+// Response shape of api.nhle.com/stats/rest/en/shiftcharts?cayenneExp=gameId={gamePk}
+type ShiftChartResponse struct {
+	Data  []Shift `json:"data"`
+	Total int     `json:"total"`
+}
+
+type Shift struct {
+	Id               int     `json:"id"`
+	GameId           int     `json:"gameId"`
+	PlayerId         int     `json:"playerId"`
+	TeamId           int     `json:"teamId"`
+	Period           int     `json:"period"`
+	ShiftNumber      int     `json:"shiftNumber"`
+	StartTime        string  `json:"startTime"`
+	EndTime          string  `json:"endTime"`
+	Duration         *string `json:"duration"`
+	TypeCode         int     `json:"typeCode"`
+	DetailCode       int     `json:"detailCode"`
+	EventDescription *string `json:"eventDescription"`
+}
+
+// typeCode of shift rows; goal markers in the same report use 505
+const ShiftTypeCode = 517
+
+// The shift rows of the report, without its goal markers
+func (r ShiftChartResponse) Shifts() []Shift {
+	shifts := make([]Shift, 0, len(r.Data))
+	for _, shift := range r.Data {
+		if shift.TypeCode == ShiftTypeCode {
+			shifts = append(shifts, shift)
+		}
+	}
+	return shifts
+}
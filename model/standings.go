@@ -0,0 +1,32 @@
+package model
+
+// Response shape of api-web.nhle.com/v1/standings/{date}
+type StandingsResponse struct {
+	WildCardIndicator bool       `json:"wildCardIndicator"`
+	Standings         []Standing `json:"standings"`
+}
+
+type Standing struct {
+	SeasonId       int         `json:"seasonId"`
+	ConferenceName string      `json:"conferenceName"`
+	DivisionName   string      `json:"divisionName"`
+	TeamName       Translation `json:"teamName"`
+	TeamCommonName Translation `json:"teamCommonName"`
+	TeamAbbrev     Translation `json:"teamAbbrev"`
+}
+
+// Standings carry no team or conference ids; teams are known by Abbrev
+func (r StandingsResponse) ToTeams() []Team {
+	teams := make([]Team, 0, len(r.Standings))
+	for _, standing := range r.Standings {
+		teams = append(teams, Team{
+			Season:     Season(standing.SeasonId),
+			Abbrev:     standing.TeamAbbrev.Default,
+			Name:       standing.TeamName.Default,
+			CommonName: standing.TeamCommonName.Default,
+			Conference: Conference{Name: standing.ConferenceName},
+			Division:   Division{Name: standing.DivisionName},
+		})
+	}
+	return teams
+}
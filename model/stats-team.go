@@ -0,0 +1,30 @@
+package model
+
+// Response shape of api.nhle.com/stats/rest/en/team. Lists every team the
+// league has had, including relocated and defunct ones.
+type StatsTeamsResponse struct {
+	Data  []StatsTeam `json:"data"`
+	Total int         `json:"total"`
+}
+
+type StatsTeam struct {
+	Id          int    `json:"id"`
+	FranchiseId int    `json:"franchiseId"`
+	FullName    string `json:"fullName"`
+	RawTricode  string `json:"rawTricode"`
+	TriCode     string `json:"triCode"`
+}
+
+// Teams carry no season, common name or alignment here
+func (r StatsTeamsResponse) ToTeams() []Team {
+	teams := make([]Team, 0, len(r.Data))
+	for _, team := range r.Data {
+		teams = append(teams, Team{
+			Id:          team.Id,
+			Abbrev:      team.TriCode,
+			Name:        team.FullName,
+			FranchiseId: team.FranchiseId,
+		})
+	}
+	return teams
+}
@@ -0,0 +1,81 @@
+package model
+
+// Response shapes of the legacy statsapi.web.nhl.com API, the only source
+// for games before the gamecenter API
+
+// Response shape of statsapi /game/{gamePk}/feed/live
+type GameResponse struct {
+	GameData GameData `json:"gameData"`
+	LiveData LiveData `json:"liveData"`
+}
+
+type GameData struct {
+	Game     GameInfo  `json:"game"`
+	DateTime DateTime  `json:"datetime"`
+	Teams    GameTeams `json:"teams"`
+}
+
+type GameInfo struct {
+	GamePk int    `json:"pk"`
+	Season string `json:"season"`
+	Type   string `json:"type"`
+}
+
+type DateTime struct {
+	DateTime string `json:"dateTime"`
+}
+
+type GameTeams struct {
+	AwayTeam TeamRef `json:"away"`
+	HomeTeam TeamRef `json:"home"`
+}
+
+type LiveData struct {
+	Plays    Plays    `json:"plays"`
+	Boxscore Boxscore `json:"boxscore"`
+}
+
+type Plays struct {
+	AllPlays []Play `json:"allPlays"`
+}
+
+type Boxscore struct {
+	BoxscoreTeams BoxscoreTeams `json:"teams"`
+}
+
+type BoxscoreTeams struct {
+	BoxscoreTeamAway BoxscoreTeam `json:"away"`
+	BoxscoreTeamHome BoxscoreTeam `json:"home"`
+}
+
+type BoxscoreTeam struct {
+	Team    TeamRef `json:"team"`
+	Goalies []int   `json:"goalies"`
+}
+
+func (r GameResponse) ToGame() (Game, error) {
+	game := r.GameData
+	season, err := ParseSeason(game.Game.Season)
+	if err != nil {
+		return Game{}, err
+	}
+
+	return Game{
+		GamePk:     game.Game.GamePk,
+		Season:     season,
+		GameType:   game.Game.Type,
+		StartTime:  game.DateTime.DateTime,
+		AwayTeamId: game.Teams.AwayTeam.Id,
+		HomeTeamId: game.Teams.HomeTeam.Id,
+	}, nil
+}
+
+func (r GameResponse) ToPlays() []Play {
+	return r.LiveData.Plays.AllPlays
+}
+
+// Ids of every goalie dressed by either team
+func (r GameResponse) Goalies() []int {
+	teams := r.LiveData.Boxscore.BoxscoreTeams
+	return append(append([]int{}, teams.BoxscoreTeamAway.Goalies...), teams.BoxscoreTeamHome.Goalies...)
+}
@@ -0,0 +1,29 @@
+package model
+
+// A team as it stood in one season. Teams are renamed and realigned between
+// seasons, so every field other than Id is specific to Season.
+type Team struct {
+	Id          int
+	Season      Season
+	Abbrev      string
+	Name        string
+	CommonName  string
+	FranchiseId int
+	Conference  Conference
+	Division    Division
+}
+
+type Conference struct {
+	Id   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type Division struct {
+	Id   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// A reference to a team by id, as feeds embed them in games and plays
+type TeamRef struct {
+	Id int `json:"id"`
+}
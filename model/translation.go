@@ -0,0 +1,7 @@
+package model
+
+// A string the api-web API localizes. Only Default is stored.
+type Translation struct {
+	Default string `json:"default"`
+	Fr      string `json:"fr,omitempty"`
+}
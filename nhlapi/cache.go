@@ -0,0 +1,159 @@
+package nhlapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gavswe19/ice-pipelines/database"
+)
+
+const (
+	// Where responses are cached, e.g. file:///tmp/nhl-api-cache, or mysql:
+	// for the http_response_cache table. Caching is off when unset.
+	CacheURLEnv = "NHL_API_CACHE_URL"
+	// Per-endpoint TTL overrides, e.g. "standings=30m,player/landing=6h"
+	CacheTTLEnv = "NHL_API_CACHE_TTL"
+)
+
+// A TTL that serves a cached response without ever revalidating it
+const Forever time.Duration = math.MaxInt64
+
+// How long each endpoint's responses are served from the cache before the
+// API is asked again. Endpoints not listed are revalidated on every request.
+// Feeds of official final games are kept forever regardless. Shift charts
+// lag the feed and are corrected after the game, so they only get a TTL.
+var DefaultTTLs = map[string]time.Duration{
+	EndpointShiftCharts:   time.Hour,
+	EndpointStandings:     time.Hour,
+	EndpointSchedule:      time.Hour,
+	EndpointStatsTeams:    24 * time.Hour,
+	EndpointPlayerLanding: 24 * time.Hour,
+	EndpointRoster:        24 * time.Hour,
+	EndpointClubStats:     24 * time.Hour,
+}
+
+// A response as it was last fetched, with the validators to ask the API
+// whether it has changed since
+type CachedResponse struct {
+	Body         []byte
+	ETag         string
+	LastModified string
+	FetchedAt    time.Time
+	// The document can no longer change, e.g. the feed of a final game
+	Final bool
+}
+
+type Cache interface {
+	// Reports false when key is not cached
+	Get(ctx context.Context, key string) (CachedResponse, bool, error)
+	Put(ctx context.Context, key string, response CachedResponse) error
+}
+
+func OpenCache(location string) (Cache, error) {
+	cacheUrl, err := url.Parse(location)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s %q: %w", CacheURLEnv, location, err)
+	}
+
+	switch cacheUrl.Scheme {
+	case "file", "":
+		return NewFileCache(cacheUrl.Path), nil
+	case "mysql":
+		db, err := database.GetDatabase()
+		if err != nil {
+			return nil, err
+		}
+		return NewSQLCache(db), nil
+	}
+	return nil, fmt.Errorf("unsupported cache scheme %q", cacheUrl.Scheme)
+}
+
+// Parses CacheTTLEnv over DefaultTTLs
+func ttlsFromEnv() (map[string]time.Duration, error) {
+	ttls := make(map[string]time.Duration, len(DefaultTTLs))
+	for endpoint, ttl := range DefaultTTLs {
+		ttls[endpoint] = ttl
+	}
+
+	value := os.Getenv(CacheTTLEnv)
+	if value == "" {
+		return ttls, nil
+	}
+
+	for _, pair := range strings.Split(value, ",") {
+		endpoint, duration, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid %s entry %q", CacheTTLEnv, pair)
+		}
+		if duration == "forever" {
+			ttls[endpoint] = Forever
+			continue
+		}
+		ttl, err := time.ParseDuration(duration)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s entry %q: %w", CacheTTLEnv, pair, err)
+		}
+		ttls[endpoint] = ttl
+	}
+	return ttls, nil
+}
+
+func cacheKey(endpoint string, params url.Values) string {
+	return endpoint + "?" + params.Encode()
+}
+
+func (c *Client) isFresh(endpoint string, cached CachedResponse) bool {
+	return cached.Final || time.Since(cached.FetchedAt) < c.ttls[endpoint]
+}
+
+// Whether a response for endpoint can no longer change. Legacy feeds are all
+// of finished seasons; gamecenter documents say whether the game is over.
+// FINAL is provisional until the league signs the game off as OFF.
+func isFinal(endpoint string, body []byte) bool {
+	switch endpoint {
+	case EndpointLegacyGameFeed:
+		return true
+	case EndpointPlayByPlay, EndpointBoxscore:
+		var game struct {
+			GameState string `json:"gameState"`
+		}
+		if json.Unmarshal(body, &game) != nil {
+			return false
+		}
+		return game.GameState == "OFF"
+	}
+	return false
+}
+
+// Cache failures are logged rather than failing the request; the API is
+// the source of truth
+func (c *Client) cachedResponse(ctx context.Context, key string) (CachedResponse, bool) {
+	if c.cache == nil {
+		return CachedResponse{}, false
+	}
+
+	cached, found, err := c.cache.Get(ctx, key)
+	if err != nil {
+		fmt.Println("Error reading cached response:", err)
+		return CachedResponse{}, false
+	}
+	return cached, found
+}
+
+func (c *Client) cacheResponse(ctx context.Context, endpoint string, params url.Values, response CachedResponse) {
+	if c.cache == nil {
+		return
+	}
+
+	response.Final = isFinal(endpoint, response.Body)
+	err := c.cache.Put(ctx, cacheKey(endpoint, params), response)
+	if err != nil {
+		fmt.Println("Error caching response:", err)
+	}
+}
@@ -0,0 +1,269 @@
+package nhlapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gavswe19/ice-pipelines/archive"
+	"github.com/gavswe19/ice-pipelines/failure"
+	"golang.org/x/time/rate"
+)
+
+// Requests per second across the whole client. Defaults to DefaultRateLimit.
+const RateLimitEnv = "NHL_API_RATE_LIMIT"
+
+const (
+	DefaultTimeout    = 30 * time.Second
+	DefaultRateLimit  = 5
+	DefaultMaxRetries = 4
+	DefaultUserAgent  = "ice-pipelines (+https://github.com/gavswe19/ice-pipelines)"
+)
+
+const (
+	initialBackoff = 500 * time.Millisecond
+	maxBackoff     = 30 * time.Second
+)
+
+type Config struct {
+	// Per request, retries excluded
+	Timeout time.Duration
+	// Requests per second. Negative disables the limit.
+	RateLimit  float64
+	MaxRetries int
+	UserAgent  string
+	// Payloads are archived here, or read from it when replaying
+	Archive *archive.Archive
+	// Responses are served from here within their endpoint's TTL and
+	// revalidated with conditional requests after. Nil disables caching.
+	Cache Cache
+	// Per endpoint. Defaults to DefaultTTLs.
+	TTLs map[string]time.Duration
+}
+
+// Client for the NHL APIs. Failed requests are retried with exponential
+// backoff unless the failure is permanent, and every request waits on a
+// shared rate limit.
+type Client struct {
+	httpClient *http.Client
+	limiter    *rate.Limiter
+	maxRetries int
+	userAgent  string
+	archive    *archive.Archive
+	cache      Cache
+	ttls       map[string]time.Duration
+}
+
+// Zero fields of cfg take their defaults
+func New(cfg Config) *Client {
+	if cfg.Timeout == 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+	if cfg.RateLimit == 0 {
+		cfg.RateLimit = DefaultRateLimit
+	}
+	if cfg.MaxRetries == 0 {
+		cfg.MaxRetries = DefaultMaxRetries
+	}
+	if cfg.UserAgent == "" {
+		cfg.UserAgent = DefaultUserAgent
+	}
+	if cfg.TTLs == nil {
+		cfg.TTLs = DefaultTTLs
+	}
+
+	limit := rate.Inf
+	if cfg.RateLimit > 0 {
+		limit = rate.Limit(cfg.RateLimit)
+	}
+
+	return &Client{
+		httpClient: &http.Client{Timeout: cfg.Timeout},
+		limiter:    rate.NewLimiter(limit, 1),
+		maxRetries: cfg.MaxRetries,
+		userAgent:  cfg.UserAgent,
+		archive:    cfg.Archive,
+		cache:      cfg.Cache,
+		ttls:       cfg.TTLs,
+	}
+}
+
+// Builds a client with the archive, cache and rate limit described by the
+// environment
+func FromEnv(ctx context.Context) (*Client, error) {
+	payloadArchive, err := archive.FromEnv(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := Config{Archive: payloadArchive}
+	if value := os.Getenv(RateLimitEnv); value != "" {
+		cfg.RateLimit, err = strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q: %w", RateLimitEnv, value, err)
+		}
+	}
+	if location := os.Getenv(CacheURLEnv); location != "" {
+		cfg.Cache, err = OpenCache(location)
+		if err != nil {
+			return nil, err
+		}
+		cfg.TTLs, err = ttlsFromEnv()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return New(cfg), nil
+}
+
+// Nil when archiving is off
+func (c *Client) Archive() *archive.Archive {
+	return c.archive
+}
+
+func (c *Client) Replaying() bool {
+	return c.archive.Replaying()
+}
+
+// Fetches apiUrl, or its archived copy when replaying, and decodes the JSON
+// body into responseObject. A body that does not decode will not decode on
+// retry either, so it is permanent.
+func (c *Client) getJson(ctx context.Context, endpoint string, params url.Values, apiUrl string, responseObject interface{}) error {
+	responseData, err := c.fetch(ctx, endpoint, params, apiUrl)
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(responseData, responseObject)
+	if err != nil {
+		return failure.Permanent(fmt.Errorf("failed to decode %s: %w", apiUrl, err))
+	}
+	return nil
+}
+
+// A fresh cached response is returned without touching the API or the
+// archive. Otherwise the request is conditional on the cached validators, so
+// an unchanged document costs the API a 304.
+func (c *Client) fetch(ctx context.Context, endpoint string, params url.Values, apiUrl string) ([]byte, error) {
+	var cached *CachedResponse
+	if !c.Replaying() {
+		response, found := c.cachedResponse(ctx, cacheKey(endpoint, params))
+		if found && c.isFresh(endpoint, response) {
+			return response.Body, nil
+		}
+		if found {
+			cached = &response
+		}
+	}
+
+	var fetched *CachedResponse
+	data, err := c.archive.Fetch(ctx, endpoint, params, func() ([]byte, error) {
+		response, err := c.get(ctx, apiUrl, cached)
+		fetched = &response
+		return response.Body, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if fetched != nil {
+		c.cacheResponse(ctx, endpoint, params, *fetched)
+	}
+	return data, nil
+}
+
+func (c *Client) get(ctx context.Context, apiUrl string, cached *CachedResponse) (CachedResponse, error) {
+	backoff := initialBackoff
+	for attempt := 0; ; attempt++ {
+		response, retryAfter, err := c.do(ctx, apiUrl, cached)
+		if err == nil || failure.IsPermanent(err) || attempt == c.maxRetries || ctx.Err() != nil {
+			return response, err
+		}
+
+		// Full jitter keeps concurrent Lambdas from retrying in lockstep
+		wait := time.Duration(rand.Int63n(int64(backoff)))
+		if retryAfter > wait {
+			wait = retryAfter
+		}
+		fmt.Printf("Retrying %s in %s: %s\n", apiUrl, wait, err)
+
+		select {
+		case <-ctx.Done():
+			return CachedResponse{}, ctx.Err()
+		case <-time.After(wait):
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+// Makes a single request, conditional on cached when it is set. A 304
+// returns cached with the new fetch time. Returns how long the server asked
+// us to wait when it rejected the request.
+func (c *Client) do(ctx context.Context, apiUrl string, cached *CachedResponse) (CachedResponse, time.Duration, error) {
+	err := c.limiter.Wait(ctx)
+	if err != nil {
+		return CachedResponse{}, 0, err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, apiUrl, nil)
+	if err != nil {
+		return CachedResponse{}, 0, failure.Permanent(err)
+	}
+	request.Header.Set("User-Agent", c.userAgent)
+	request.Header.Set("Accept", "application/json")
+	if cached != nil {
+		if cached.ETag != "" {
+			request.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			request.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return CachedResponse{}, 0, err
+	}
+	defer response.Body.Close()
+
+	fetched := CachedResponse{
+		ETag:         response.Header.Get("ETag"),
+		LastModified: response.Header.Get("Last-Modified"),
+		FetchedAt:    time.Now(),
+	}
+
+	if response.StatusCode == http.StatusNotModified && cached != nil {
+		fetched.Body = cached.Body
+		if fetched.ETag == "" {
+			fetched.ETag = cached.ETag
+		}
+		if fetched.LastModified == "" {
+			fetched.LastModified = cached.LastModified
+		}
+		return fetched, 0, nil
+	}
+
+	err = failure.CheckStatus(response)
+	if err != nil {
+		return CachedResponse{}, retryAfter(response), err
+	}
+
+	fetched.Body, err = io.ReadAll(response.Body)
+	return fetched, 0, err
+}
+
+// Only the delay-seconds form of Retry-After is honoured
+func retryAfter(response *http.Response) time.Duration {
+	seconds, err := strconv.Atoi(response.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return min(time.Duration(seconds)*time.Second, maxBackoff)
+}
@@ -0,0 +1,126 @@
+package nhlapi
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/gavswe19/ice-pipelines/model"
+)
+
+const (
+	webBaseUrl    = "https://api-web.nhle.com/v1"
+	statsBaseUrl  = "https://api.nhle.com/stats/rest/en"
+	legacyBaseUrl = "https://statsapi.web.nhl.com/api/v1"
+)
+
+// Names payloads are archived under. Replays look games and players up by
+// these.
+const (
+	EndpointSchedule       = "schedule"
+	EndpointStatsTeams     = "stats/team"
+	EndpointLegacyGameFeed = "statsapi/game-feed"
+	EndpointPlayByPlay     = "gamecenter/play-by-play"
+	EndpointBoxscore       = "gamecenter/boxscore"
+	EndpointScoreNow       = "score/now"
+	EndpointShiftCharts    = "stats/shiftcharts"
+	EndpointPlayerLanding  = "player/landing"
+	EndpointRoster         = "roster"
+	EndpointStandings      = "standings"
+	EndpointClubStats      = "club-stats"
+)
+
+const dateFormat = "2006-01-02"
+
+// Schedule for the week starting at date
+func (c *Client) Schedule(ctx context.Context, date time.Time) (model.ScheduleResponse, error) {
+	dateStr := date.Format(dateFormat)
+	apiUrl := fmt.Sprintf("%s/schedule/%s", webBaseUrl, dateStr)
+	var response model.ScheduleResponse
+	err := c.getJson(ctx, EndpointSchedule, url.Values{"date": {dateStr}}, apiUrl, &response)
+	return response, err
+}
+
+// Every team the league has had, with ids and franchises
+func (c *Client) StatsTeams(ctx context.Context) (model.StatsTeamsResponse, error) {
+	var response model.StatsTeamsResponse
+	err := c.getJson(ctx, EndpointStatsTeams, nil, statsBaseUrl+"/team", &response)
+	return response, err
+}
+
+// Legacy stats API live feed, the only source for games before the
+// gamecenter API
+func (c *Client) LegacyGameFeed(ctx context.Context, gamePk int) (model.GameResponse, error) {
+	apiUrl := fmt.Sprintf("%s/game/%d/feed/live", legacyBaseUrl, gamePk)
+	var response model.GameResponse
+	err := c.getJson(ctx, EndpointLegacyGameFeed, gamePkParams(gamePk), apiUrl, &response)
+	return response, err
+}
+
+func (c *Client) PlayByPlay(ctx context.Context, gamePk int) (model.GamecenterResponse, error) {
+	apiUrl := fmt.Sprintf("%s/gamecenter/%d/play-by-play", webBaseUrl, gamePk)
+	var response model.GamecenterResponse
+	err := c.getJson(ctx, EndpointPlayByPlay, gamePkParams(gamePk), apiUrl, &response)
+	return response, err
+}
+
+func (c *Client) Boxscore(ctx context.Context, gamePk int) (model.BoxscoreResponse, error) {
+	apiUrl := fmt.Sprintf("%s/gamecenter/%d/boxscore", webBaseUrl, gamePk)
+	var response model.BoxscoreResponse
+	err := c.getJson(ctx, EndpointBoxscore, gamePkParams(gamePk), apiUrl, &response)
+	return response, err
+}
+
+// Today's scoreboard
+func (c *Client) ScoreNow(ctx context.Context) (model.ScoreResponse, error) {
+	var response model.ScoreResponse
+	err := c.getJson(ctx, EndpointScoreNow, nil, webBaseUrl+"/score/now", &response)
+	return response, err
+}
+
+func (c *Client) ShiftCharts(ctx context.Context, gamePk int) (model.ShiftChartResponse, error) {
+	apiUrl := fmt.Sprintf("%s/shiftcharts?cayenneExp=gameId=%d", statsBaseUrl, gamePk)
+	var response model.ShiftChartResponse
+	err := c.getJson(ctx, EndpointShiftCharts, gamePkParams(gamePk), apiUrl, &response)
+	return response, err
+}
+
+func (c *Client) PlayerLanding(ctx context.Context, playerId int) (model.PlayerLandingResponse, error) {
+	apiUrl := fmt.Sprintf("%s/player/%d/landing", webBaseUrl, playerId)
+	var response model.PlayerLandingResponse
+	err := c.getJson(ctx, EndpointPlayerLanding, url.Values{"playerId": {strconv.Itoa(playerId)}}, apiUrl, &response)
+	return response, err
+}
+
+func (c *Client) Roster(ctx context.Context, teamAbbrev string, season model.Season) (model.RosterResponse, error) {
+	apiUrl := fmt.Sprintf("%s/roster/%s/%d", webBaseUrl, teamAbbrev, season)
+	params := url.Values{"team": {teamAbbrev}, "season": {season.String()}}
+	var response model.RosterResponse
+	err := c.getJson(ctx, EndpointRoster, params, apiUrl, &response)
+	return response, err
+}
+
+// League standings as of date
+func (c *Client) Standings(ctx context.Context, date time.Time) (model.StandingsResponse, error) {
+	dateStr := date.Format(dateFormat)
+	apiUrl := fmt.Sprintf("%s/standings/%s", webBaseUrl, dateStr)
+	var response model.StandingsResponse
+	err := c.getJson(ctx, EndpointStandings, url.Values{"date": {dateStr}}, apiUrl, &response)
+	return response, err
+}
+
+// Skater and goalie stats for a team's season. gameType is 2 for the regular
+// season and 3 for the playoffs.
+func (c *Client) ClubStats(ctx context.Context, teamAbbrev string, season model.Season, gameType int) (model.ClubStatsResponse, error) {
+	apiUrl := fmt.Sprintf("%s/club-stats/%s/%d/%d", webBaseUrl, teamAbbrev, season, gameType)
+	params := url.Values{"team": {teamAbbrev}, "season": {season.String()}, "gameType": {strconv.Itoa(gameType)}}
+	var response model.ClubStatsResponse
+	err := c.getJson(ctx, EndpointClubStats, params, apiUrl, &response)
+	return response, err
+}
+
+func gamePkParams(gamePk int) url.Values {
+	return url.Values{"gamePk": {strconv.Itoa(gamePk)}}
+}
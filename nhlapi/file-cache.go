@@ -0,0 +1,72 @@
+package nhlapi
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Cache kept on the local filesystem with one file per response
+type FileCache struct {
+	root string
+}
+
+func NewFileCache(root string) *FileCache {
+	return &FileCache{root: root}
+}
+
+func (c *FileCache) Get(ctx context.Context, key string) (CachedResponse, bool, error) {
+	data, err := os.ReadFile(c.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return CachedResponse{}, false, nil
+	}
+	if err != nil {
+		return CachedResponse{}, false, err
+	}
+
+	var response CachedResponse
+	err = json.Unmarshal(data, &response)
+	if err != nil {
+		return CachedResponse{}, false, err
+	}
+	return response, true, nil
+}
+
+// Writes through a temporary file so a concurrent Get never sees a partial
+// response
+func (c *FileCache) Put(ctx context.Context, key string, response CachedResponse) error {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(c.root, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(c.root, "*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path(key))
+}
+
+// Keys hold characters that are not safe in file names, so files are named
+// by the key's hash
+func (c *FileCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.root, hex.EncodeToString(sum[:])+".json")
+}
@@ -0,0 +1,50 @@
+package nhlapi
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// Cache kept in the http_response_cache table, shared by every Lambda. The
+// table is provisioned with the rest of the schema, keyed by cache_key, with
+// body, etag, last_modified, fetched_at and final columns.
+type SQLCache struct {
+	db *sql.DB
+}
+
+func NewSQLCache(db *sql.DB) *SQLCache {
+	return &SQLCache{db: db}
+}
+
+func (c *SQLCache) Get(ctx context.Context, key string) (CachedResponse, bool, error) {
+	var response CachedResponse
+	err := c.db.QueryRowContext(ctx,
+		"SELECT body, etag, last_modified, fetched_at, final FROM http_response_cache WHERE cache_key = ?", key,
+	).Scan(&response.Body, &response.ETag, &response.LastModified, &response.FetchedAt, &response.Final)
+
+	if err == sql.ErrNoRows {
+		return CachedResponse{}, false, nil
+	}
+	if err != nil {
+		return CachedResponse{}, false, fmt.Errorf("failed to read http_response_cache: %w", err)
+	}
+	return response, true, nil
+}
+
+func (c *SQLCache) Put(ctx context.Context, key string, response CachedResponse) error {
+	_, err := c.db.ExecContext(ctx, `
+	INSERT INTO http_response_cache (cache_key, body, etag, last_modified, fetched_at, final)
+	VALUES (?, ?, ?, ?, ?, ?)
+	ON DUPLICATE KEY UPDATE
+		body = VALUES(body),
+		etag = VALUES(etag),
+		last_modified = VALUES(last_modified),
+		fetched_at = VALUES(fetched_at),
+		final = VALUES(final)`,
+		key, response.Body, response.ETag, response.LastModified, response.FetchedAt.UTC(), response.Final)
+	if err != nil {
+		return fmt.Errorf("failed to write http_response_cache: %w", err)
+	}
+	return nil
+}
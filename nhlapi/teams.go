@@ -0,0 +1,40 @@
+package nhlapi
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gavswe19/ice-pipelines/model"
+)
+
+// Teams as they stood on date. Standings give the season's names and
+// alignment but no ids, so ids and franchises come from the stats API,
+// joined on abbreviation. Empty outside the season, when there are no
+// standings.
+func (c *Client) Teams(ctx context.Context, date time.Time) ([]model.Team, error) {
+	standings, err := c.Standings(ctx, date)
+	if err != nil {
+		return nil, err
+	}
+	statsTeams, err := c.StatsTeams(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	idTeams := make(map[string]model.Team)
+	for _, team := range statsTeams.ToTeams() {
+		idTeams[team.Abbrev] = team
+	}
+
+	teams := standings.ToTeams()
+	for i, team := range teams {
+		idTeam, ok := idTeams[team.Abbrev]
+		if !ok {
+			return nil, fmt.Errorf("no stats API team for %s", team.Abbrev)
+		}
+		teams[i].Id = idTeam.Id
+		teams[i].FranchiseId = idTeam.FranchiseId
+	}
+	return teams, nil
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"flag"
+	"log"
+
+	"github.com/gavswe19/ice-pipelines/aggregates"
+	"github.com/gavswe19/ice-pipelines/database"
+)
+
+// Rebuilds per-player zone start ratios for a season
+func main() {
+	season := flag.String("season", "20232024", "season to aggregate, e.g. 20232024")
+	flag.Parse()
+
+	db, err := database.GetDatabase()
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+
+	if err := aggregates.BuildPlayerSeasonZoneStarts(db, *season); err != nil {
+		log.Fatalf("Failed to build player zone starts: %v", err)
+	}
+}
@@ -4,52 +4,75 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"strconv"
 	"time"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/gavswe19/ice-pipelines/message"
+	"github.com/gavswe19/ice-pipelines/nhlapi"
 )
 
 type GatewayResponse events.APIGatewayProxyResponse
 
+// Set from the environment in main
+var nhlClient *nhlapi.Client
+
 func main() {
+	var err error
+	nhlClient, err = nhlapi.FromEnv(context.TODO())
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	yesterday := time.Now().AddDate(0, 0, -1)
-	PopulateGameQueue(yesterday)
-	// runYear()
+	if err := PopulateGameQueue(context.TODO(), yesterday); err != nil {
+		log.Fatal(err)
+	}
+	// runYear(context.TODO())
 }
 
-func runYear() {
+func runYear(ctx context.Context) error {
 	dte := time.Date(2021, 10, 1, 0, 0, 0, 0, time.Local)
 	stopDate := time.Date(2022, 8, 1, 0, 0, 0, 0, time.Local)
 
 	for dte.UnixMilli() < stopDate.UnixMilli() {
 		fmt.Println(dte.Format("2006-01-02"))
-		PopulateGameQueue(dte)
+		if err := PopulateGameQueue(ctx, dte); err != nil {
+			return err
+		}
 		dte = dte.AddDate(0, 0, 1)
 	}
+	return nil
 }
 
-func PopulateGameQueue(dte time.Time) {
-	gamePkList := GetScheduleGames(dte)
+func PopulateGameQueue(ctx context.Context, dte time.Time) error {
+	gamePkList, err := GetScheduleGames(ctx, dte)
+	if err != nil {
+		return err
+	}
 	fmt.Println(gamePkList)
 
 	sqsClient := getSqsClient()
-	ctx := context.TODO()
 
 	for _, gamePk := range gamePkList {
+		body, err := message.New(message.EntityGame, gamePk, "populate-game-queue").Encode()
+		if err != nil {
+			return err
+		}
+
 		queueMessage := &sqs.SendMessageInput{
-			MessageBody: aws.String(strconv.Itoa(gamePk)),
+			MessageBody: aws.String(body),
 			QueueUrl:    aws.String("https://sqs.us-east-1.amazonaws.com/271463937680/ice-game-queue"),
 		}
-		_, err := sqsClient.SendMessage(ctx, queueMessage)
+		_, err = sqsClient.SendMessage(ctx, queueMessage)
 
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 	}
+	return nil
 }
 
 func getSqsClient() *sqs.Client {
@@ -1,52 +1,19 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"io"
-	"log"
-	"net/http"
 )
 
-// Define the structures that represent the JSON response
-
-type PlayerStatsResponse struct {
-	Season   string   `json:"season"`
-	GameType int      `json:"gameType"`
-	Skaters  []Player `json:"skaters"`
-	Goalies  []Player `json:"goalies"`
-}
-
-type Player struct {
-	PlayerId int `json:"playerId"`
-}
-
-func getTeamPlayerIdList(teamAbbrev string) []int {
-	url := fmt.Sprintf("https://api-web.nhle.com/v1/club-stats/%s/20212022/2", teamAbbrev)
-
-	resp, err := http.Get(url)
+func getTeamPlayerIdList(ctx context.Context, teamAbbrev string) ([]int, error) {
+	stats, err := nhlClient.ClubStats(ctx, teamAbbrev, 20212022, 2)
 	if err != nil {
-		log.Fatalf("Error fetching data from API: %v", err)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		log.Fatalf("Error reading response body: %v", err)
-	}
-
-	var stats PlayerStatsResponse
-	err = json.Unmarshal(body, &stats)
-	if err != nil {
-		log.Fatalf("Error parsing JSON: %v", err)
+		return nil, fmt.Errorf("error fetching club stats: %w", err)
 	}
 
 	var playerIds []int
-	for _, skater := range stats.Skaters {
-		playerIds = append(playerIds, skater.PlayerId)
-	}
-	for _, goalie := range stats.Goalies {
-		playerIds = append(playerIds, goalie.PlayerId)
+	for _, player := range stats.ToPlayers() {
+		playerIds = append(playerIds, player.Id)
 	}
 
 	fmt.Println("Player IDs:")
@@ -54,5 +21,5 @@ func getTeamPlayerIdList(teamAbbrev string) []int {
 		fmt.Println(id)
 	}
 
-	return playerIds
+	return playerIds, nil
 }
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"strconv"
 
 	// "log"
 	// "strconv"
@@ -12,26 +11,48 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/gavswe19/ice-pipelines/message"
+	"github.com/gavswe19/ice-pipelines/nhlapi"
 )
 
+// Set from the environment in main
+var nhlClient *nhlapi.Client
+
 func main() {
-	teamAbvList := getAllTeamAbv()
+	var err error
+	nhlClient, err = nhlapi.FromEnv(context.TODO())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx := context.TODO()
+	teamAbvList, err := getAllTeamAbv(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
 	fmt.Println(teamAbvList)
 	fmt.Println(len(teamAbvList))
 
 	sqsClient := getSqsClient()
-	ctx := context.TODO()
 
 	for _, teamAbv := range teamAbvList {
 		fmt.Println(teamAbv, " ----------------------- ")
-		playerIdList := getTeamPlayerIdList(teamAbv)
+		playerIdList, err := getTeamPlayerIdList(ctx, teamAbv)
+		if err != nil {
+			log.Fatal(err)
+		}
 		for _, playerId := range playerIdList {
 			fmt.Println(playerId)
+			body, err := message.New(message.EntityPlayer, playerId, "populate-player-queue").WithSeason(20212022).Encode()
+			if err != nil {
+				log.Fatal(err)
+			}
+
 			queueMessage := &sqs.SendMessageInput{
-				MessageBody: aws.String(strconv.Itoa(playerId)),
+				MessageBody: aws.String(body),
 				QueueUrl:    aws.String("https://sqs.us-east-1.amazonaws.com/271463937680/player-queue"),
 			}
-			_, err := sqsClient.SendMessage(ctx, queueMessage)
+			_, err = sqsClient.SendMessage(ctx, queueMessage)
 
 			if err != nil {
 				log.Fatal(err)
@@ -5,28 +5,40 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
-	"strconv"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
 	"github.com/gavswe19/ice-pipelines/database"
+	"github.com/gavswe19/ice-pipelines/message"
 )
 
 func main() {
-	db := database.GetDatabase()
-	playerIdList := fetchAllPlayers(db, 20212022)
+	db, err := database.GetDatabase()
+	if err != nil {
+		log.Fatal(err)
+	}
+	season := 20212022
+	playerIdList, err := fetchAllPlayers(db, season)
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	sqsClient := getSqsClient()
 	ctx := context.TODO()
 
 	for _, playerId := range playerIdList {
 		fmt.Println(playerId)
+		body, err := message.New(message.EntityPlayerSeasonTotals, playerId, "populate-player-season-totals-queue").WithSeason(season).Encode()
+		if err != nil {
+			log.Fatal(err)
+		}
+
 		queueMessage := &sqs.SendMessageInput{
-			MessageBody: aws.String(strconv.Itoa(playerId)),
+			MessageBody: aws.String(body),
 			QueueUrl:    aws.String("https://sqs.us-east-1.amazonaws.com/271463937680/ice-player-season-totals-queue"),
 		}
-		_, err := sqsClient.SendMessage(ctx, queueMessage)
+		_, err = sqsClient.SendMessage(ctx, queueMessage)
 
 		if err != nil {
 			log.Fatal(err)
@@ -34,13 +46,14 @@ func main() {
 	}
 }
 
-func fetchAllPlayers(db *sql.DB, season int) []int {
+func fetchAllPlayers(db *sql.DB, season int) ([]int, error) {
 	query := fmt.Sprintf("SELECT DISTINCT player_id FROM team_season_players WHERE season = %d", season)
 	results, err := db.Query(query)
 
 	if err != nil {
-		log.Fatal("Error retrieving team_season_playerss")
+		return nil, fmt.Errorf("error retrieving team_season_players: %w", err)
 	}
+	defer results.Close()
 
 	playerIdList := []int{}
 
@@ -48,11 +61,11 @@ func fetchAllPlayers(db *sql.DB, season int) []int {
 		var playerId int
 		err = results.Scan(&playerId)
 		if err != nil {
-			log.Fatal(err)
+			return nil, err
 		}
 		playerIdList = append(playerIdList, playerId)
 	}
-	return playerIdList
+	return playerIdList, results.Err()
 }
 
 func getSqsClient() *sqs.Client {
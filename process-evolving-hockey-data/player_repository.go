@@ -15,7 +15,10 @@ type PlayerRepository struct {
 
 // NewPlayerRepository creates a new PlayerRepository with a database connection
 func NewPlayerRepository() (*PlayerRepository, error) {
-	db := database.GetDatabase()
+	db, err := database.GetDatabase()
+	if err != nil {
+		return nil, err
+	}
 
 	return &PlayerRepository{db: db}, nil
 }
@@ -0,0 +1,36 @@
+package main
+
+type PlayerGameStatsRecord struct {
+	gamePk        int
+	playerId      int
+	teamId        int
+	position      string
+	goals         int
+	assists       int
+	shots         int
+	hits          int
+	blocks        int
+	pim           int
+	toiSeconds    int
+	toiEvSeconds  int
+	toiPpSeconds  int
+	toiShSeconds  int
+	faceoffWins   int
+	faceoffLosses int
+	saves         int
+	shotsAgainst  int
+	decision      string
+}
+
+type GoalieGameStatsRecord struct {
+	gamePk          int
+	goalieId        int
+	teamId          int
+	splitType       string
+	splitValue      string
+	shotsAgainst    int
+	saves           int
+	goalsAgainst    int
+	reboundsAllowed int
+	toiSeconds      int
+}
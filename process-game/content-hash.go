@@ -0,0 +1,21 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// Hashes the decoded source payloads of a game so a run can be matched to
+// the exact feed it was built from
+func hashContent(payloads ...interface{}) (string, error) {
+	hash := sha256.New()
+	for _, payload := range payloads {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return "", err
+		}
+		hash.Write(data)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
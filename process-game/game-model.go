@@ -1,109 +1,32 @@
 package main
 
-type GameResponse struct {
-	GameData GameData `json:"gameData"`
-	LiveData LiveData `json:"liveData"`
-}
-
-type GameData struct {
-	Game     Game     `json:"game"`
-	DateTime DateTime `json:"datetime"`
-	Teams    Teams    `json:"teams"`
-}
-
-type Game struct {
-	GamePk int    `json:"pk"`
-	Season string `json:"season"`
-	Type   string `json:"type"`
-}
-
-type DateTime struct {
-	DateTime string `json:"dateTime"`
-}
-
-type Teams struct {
-	AwayTeam Team `json:"away"`
-	HomeTeam Team `json:"home"`
-}
-
-type Team struct {
-	Id int `json:"id"`
-}
-
-type LiveData struct {
-	Plays    Plays    `json:"plays"`
-	Boxscore Boxscore `json:"boxscore"`
-}
-
-type Plays struct {
-	AllPlays []Play `json:"allPlays"`
-}
-
-type Boxscore struct {
-	BoxscoreTeams BoxscoreTeams `json:"teams"`
-}
-
-type BoxscoreTeams struct {
-	BoxscoreTeamAway BoxscoreTeam `json:"away"`
-	BoxscoreTeamHome BoxscoreTeam `json:"home"`
-}
+import (
+	"time"
 
-type BoxscoreTeam struct {
-	Team      Team              `json:"team"`
-	Goalies   []int             `json:"goalies"`
-	OnIcePlus []OnIcePlusPlayer `json:"onIcePlus"`
-}
-
-type OnIcePlusPlayer struct {
-	PlayerId      int `json:"playerId"`
-	ShiftDuration int `json:"shiftDuration"`
-}
+	"github.com/gavswe19/ice-pipelines/model"
+)
 
+// A play with the context derived while processing the game
 type Play struct {
-	Players     []Players   `json:"players"`
-	Result      Result      `json:"result"`
-	About       About       `json:"about"`
-	Coordinates Coordinates `json:"coordinates"`
-	Team        Team        `json:"team"`
-}
-
-type Players struct {
-	Player     Player `json:"player"`
-	PlayerType string `json:"playerType"`
-}
-
-type Player struct {
-	PlayerId int `json:"id"`
-}
+	model.Play
 
-type Result struct {
-	Event         string `json:"event"`
-	EventCode     string `json:"eventCode"`
-	EventTypeId   string `json:"eventTypeId"`
-	Description   string `json:"description"`
-	SecondaryType string `json:"string"`
+	Strength              StrengthState     `json:"-"`
+	NormalizedCoordinates model.Coordinates `json:"-"`
+	ShotDistance          float64           `json:"-"`
+	ShotAngle             float64           `json:"-"`
+	GameSeconds           int               `json:"-"`
+	PeriodSeconds         int               `json:"-"`
+	EventTime             *time.Time        `json:"-"`
+	AwayScoreState        int               `json:"-"`
+	HomeScoreState        int               `json:"-"`
 }
 
-type About struct {
-	EventIdx            int    `json:"eventIdx"`
-	EvendId             int    `json:"eventId"`
-	Period              int    `json:"period"`
-	PeriodType          string `json:"periodType"`
-	OrdinalNum          string `json:"ordinalNum"`
-	PeriodTime          string `json:"periodTime"`
-	PeriodTimeRemaining string `json:"periodTimeRemaining"`
-	DateTime            string `json:"dateTime"`
-	Goals               Goals  `json:"goals"`
-}
-
-type Coordinates struct {
-	X float32 `json:"x"`
-	Y float32 `json:"y"`
-}
-
-type Goals struct {
-	Away int `json:"away"`
-	Home int `json:"home"`
+// A game and its plays in the statsapi model, whichever feed they came from
+type GameFeed struct {
+	Game      model.Game
+	Plays     []Play
+	Goalies   []int
+	Positions map[int]string
 }
 
 type OnIceRecord struct {
@@ -118,4 +41,69 @@ type OnIceRecord struct {
 	skaterId5 int
 	skaterId6 int
 	goalieId  int
+
+	forwardLineHash string
+	defensePairHash string
+	forwardIds      []int
+	defenseIds      []int
+}
+
+type ShiftRecord struct {
+	gamePk        int
+	playerId      int
+	teamId        int
+	period        int
+	shiftNumber   int
+	startSeconds  int
+	endSeconds    int
+	duration      int
+	endedWithGoal bool
+}
+
+type FaceoffRecord struct {
+	gamePk        int
+	eventIdx      int
+	period        int
+	periodSeconds int
+	winnerId      int
+	winnerTeamId  int
+	loserId       int
+	loserTeamId   int
+	awayZone      string
+	homeZone      string
+	strengthState string
+}
+
+type ZoneStartRecord struct {
+	gamePk   int
+	eventIdx int
+	playerId int
+	teamId   int
+	zone     string
+}
+
+type PenaltyRecord struct {
+	gamePk            int
+	eventIdx          int
+	teamId            int
+	penalizedPlayerId int
+	servedByPlayerId  int
+	drawnByPlayerId   int
+	infraction        string
+	severity          string
+	minutes           int
+	startGameSeconds  int
+	endGameSeconds    int
+	endedByGoal       bool
+}
+
+type ShootoutAttemptRecord struct {
+	gamePk    int
+	eventIdx  int
+	round     int
+	teamId    int
+	shooterId int
+	goalieId  int
+	result    string
+	shotType  string
 }
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gavswe19/ice-pipelines/failure"
+	"github.com/gavswe19/ice-pipelines/model"
+	"github.com/gavswe19/ice-pipelines/nhlapi"
+)
+
+// First season served from api-web.nhle.com. Earlier seasons still load
+// through the statsapi GameResponse model.
+const gamecenterFirstSeason = 2023
+
+func usesLegacyFeed(gamePk int) bool {
+	return model.SeasonOfGame(gamePk).StartYear() < gamecenterFirstSeason
+}
+
+// Set from the environment in main
+var nhlClient *nhlapi.Client
+
+// Returns the game, its plays and the goalies dressed in the statsapi model
+// regardless of which feed the game was loaded from
+func GetGameFeed(ctx context.Context, gamePk int) (GameFeed, error) {
+	if usesLegacyFeed(gamePk) {
+		game, err := nhlClient.LegacyGameFeed(ctx, gamePk)
+		if err != nil {
+			return GameFeed{}, err
+		}
+		gameInfo, err := game.ToGame()
+		if err != nil {
+			return GameFeed{}, failure.Permanent(fmt.Errorf("invalid game feed for gamePk %d: %w", gamePk, err))
+		}
+		return GameFeed{
+			Game:    gameInfo,
+			Plays:   wrapPlays(game.ToPlays()),
+			Goalies: game.Goalies(),
+		}, nil
+	}
+
+	game, err := nhlClient.PlayByPlay(ctx, gamePk)
+	if err != nil {
+		return GameFeed{}, err
+	}
+	return GameFeed{
+		Game:      game.ToGame(),
+		Plays:     wrapPlays(game.ToPlays()),
+		Goalies:   game.Goalies(),
+		Positions: game.Positions(),
+	}, nil
+}
+
+func GetBoxscore(ctx context.Context, gamePk int) (model.BoxscoreResponse, error) {
+	return nhlClient.Boxscore(ctx, gamePk)
+}
+
+func wrapPlays(plays []model.Play) []Play {
+	wrapped := make([]Play, len(plays))
+	for i, play := range plays {
+		wrapped[i] = Play{Play: play}
+	}
+	return wrapped
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"math"
+
+	"github.com/gavswe19/ice-pipelines/model"
+	"github.com/gavswe19/ice-pipelines/xg"
+)
+
+// Sets coordinates on every play rotated so the event's team always attacks
+// +x, along with distance and angle to the net being attacked. Blocked shots
+// are tagged with the blocking team but rotated for the shooting team. Plays
+// without a team are normalized from the home team's perspective.
+func SetNormalizedCoordinates(feed GameFeed) {
+	homeTeamId := feed.Game.HomeTeamId
+	teamIds := [2]int{feed.Game.AwayTeamId, homeTeamId}
+	homeDirections := homeAttackingDirections(feed)
+
+	for i, play := range feed.Plays {
+		direction := homeDirections[play.About.Period]
+		teamId := xg.ShootingTeamId(play.Result.EventTypeId, play.Team.Id, teamIds)
+		if teamId != 0 && teamId != homeTeamId {
+			direction = -direction
+		}
+
+		x := float64(play.Coordinates.X) * direction
+		y := float64(play.Coordinates.Y) * direction
+
+		feed.Plays[i].NormalizedCoordinates = model.Coordinates{X: float32(x), Y: float32(y)}
+		feed.Plays[i].ShotDistance = xg.DistanceToNet(x, y)
+		feed.Plays[i].ShotAngle = xg.AngleToNet(x, y)
+	}
+}
+
+// Returns +1 for periods where the home team attacks +x and -1 otherwise.
+// The gamecenter feed states which side the home team defends; statsapi games
+// are inferred from where each team's unblocked shots were taken.
+func homeAttackingDirections(feed GameFeed) map[int]float64 {
+	homeTeamId := feed.Game.HomeTeamId
+	directions := make(map[int]float64)
+	shotWeights := make(map[int]float64)
+	lastPeriod := 0
+
+	for _, play := range feed.Plays {
+		period := play.About.Period
+		if period > lastPeriod {
+			lastPeriod = period
+		}
+
+		switch play.HomeTeamDefendingSide {
+		case "left":
+			directions[period] = 1
+			continue
+		case "right":
+			directions[period] = -1
+			continue
+		}
+
+		eventTypeId := play.Result.EventTypeId
+		if eventTypeId != "SHOT" && eventTypeId != "MISSED_SHOT" && eventTypeId != "GOAL" {
+			continue
+		}
+
+		if play.Team.Id == homeTeamId {
+			shotWeights[period] += float64(play.Coordinates.X)
+		} else {
+			shotWeights[period] -= float64(play.Coordinates.X)
+		}
+	}
+
+	for period, weight := range shotWeights {
+		if _, ok := directions[period]; ok || weight == 0 {
+			continue
+		}
+		directions[period] = math.Copysign(1, weight)
+	}
+
+	// Teams change ends every period, so fill gaps from neighbouring periods
+	for period := 1; period <= lastPeriod; period++ {
+		if _, ok := directions[period]; ok {
+			continue
+		}
+		if direction, ok := directions[period-1]; ok {
+			directions[period] = -direction
+		} else if direction, ok := directions[period+1]; ok {
+			directions[period] = -direction
+		} else {
+			directions[period] = 1
+		}
+	}
+
+	return directions
+}
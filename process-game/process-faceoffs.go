@@ -0,0 +1,93 @@
+package main
+
+import (
+	"github.com/gavswe19/ice-pipelines/model"
+	"github.com/gavswe19/ice-pipelines/xg"
+)
+
+// Builds a faceoff record for every draw, with the zone from each team's
+// perspective, and a zone start for every player whose shift began on it.
+// Strength is labelled from the home team's perspective.
+func GetFaceoffRecords(gamePk int, feed GameFeed, shifts []model.Shift) ([]FaceoffRecord, []ZoneStartRecord) {
+	awayTeamId := feed.Game.AwayTeamId
+	homeTeamId := feed.Game.HomeTeamId
+
+	var faceoffRecordList []FaceoffRecord
+	var zoneStartRecordList []ZoneStartRecord
+
+	for _, play := range feed.Plays {
+		if play.Result.EventTypeId != "FACEOFF" {
+			continue
+		}
+
+		// The faceoff's team is the winning team, so its normalized
+		// coordinates are from the winner's perspective
+		winnerTeamId := play.Team.Id
+		loserTeamId := homeTeamId
+		if winnerTeamId == homeTeamId {
+			loserTeamId = awayTeamId
+		}
+		winnerZone := faceoffZone(float64(play.NormalizedCoordinates.X))
+
+		zones := map[int]string{
+			winnerTeamId: winnerZone,
+			loserTeamId:  oppositeZone(winnerZone),
+		}
+
+		faceoff := FaceoffRecord{
+			gamePk:        gamePk,
+			eventIdx:      play.About.EventIdx,
+			period:        play.About.Period,
+			periodSeconds: model.PeriodTimeToSeconds(play.About.PeriodTime),
+			winnerTeamId:  winnerTeamId,
+			loserTeamId:   loserTeamId,
+			awayZone:      zones[awayTeamId],
+			homeZone:      zones[homeTeamId],
+			strengthState: play.Strength.Label(homeTeamId, awayTeamId),
+		}
+		for _, player := range play.Players {
+			switch player.PlayerType {
+			case "Winner":
+				faceoff.winnerId = player.Player.PlayerId
+			case "Loser":
+				faceoff.loserId = player.Player.PlayerId
+			}
+		}
+		faceoffRecordList = append(faceoffRecordList, faceoff)
+
+		for _, shift := range shifts {
+			if shift.Period != faceoff.period || model.PeriodTimeToSeconds(shift.StartTime) != faceoff.periodSeconds {
+				continue
+			}
+			zoneStartRecordList = append(zoneStartRecordList, ZoneStartRecord{
+				gamePk:   gamePk,
+				eventIdx: faceoff.eventIdx,
+				playerId: shift.PlayerId,
+				teamId:   shift.TeamId,
+				zone:     zones[shift.TeamId],
+			})
+		}
+	}
+
+	return faceoffRecordList, zoneStartRecordList
+}
+
+func faceoffZone(normalizedX float64) string {
+	switch {
+	case normalizedX > xg.BlueLineX:
+		return "O"
+	case normalizedX < -xg.BlueLineX:
+		return "D"
+	}
+	return "N"
+}
+
+func oppositeZone(zone string) string {
+	switch zone {
+	case "O":
+		return "D"
+	case "D":
+		return "O"
+	}
+	return "N"
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"time"
+
+	"github.com/gavswe19/ice-pipelines/model"
+)
+
+// Sets elapsed game and period seconds, the event timestamp and the score
+// state of every play. Score state is the goal differential from each team's
+// perspective before the play, so a goal is not counted in its own score
+// state.
+//
+// Only statsapi plays carry a wall-clock time. Gamecenter plays have none,
+// so their event timestamp is left NULL rather than estimated.
+func SetGameClock(feed GameFeed) {
+	awayTeamId := feed.Game.AwayTeamId
+
+	for i, play := range feed.Plays {
+		p := &feed.Plays[i]
+		p.PeriodSeconds = model.PeriodTimeToSeconds(play.About.PeriodTime)
+		p.GameSeconds = gameSeconds(play.About.Period, play.About.PeriodTime)
+
+		if eventTime, err := time.Parse(time.RFC3339, play.About.DateTime); err == nil {
+			p.EventTime = &eventTime
+		}
+
+		away, home := play.About.Goals.Away, play.About.Goals.Home
+		if play.Result.EventTypeId == "GOAL" {
+			if play.Team.Id == awayTeamId {
+				away--
+			} else {
+				home--
+			}
+		}
+		p.AwayScoreState = away - home
+		p.HomeScoreState = home - away
+	}
+}
@@ -3,22 +3,46 @@ package main
 import (
 	"context"
 	"database/sql"
-	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"log"
-	"net/http"
+	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/gavswe19/ice-pipelines/aggregates"
 	"github.com/gavswe19/ice-pipelines/database"
+	"github.com/gavswe19/ice-pipelines/deadletter"
+	"github.com/gavswe19/ice-pipelines/failure"
+	"github.com/gavswe19/ice-pipelines/jobs"
+	"github.com/gavswe19/ice-pipelines/message"
+	"github.com/gavswe19/ice-pipelines/model"
+	"github.com/gavswe19/ice-pipelines/nhlapi"
+	"github.com/gavswe19/ice-pipelines/xg"
 )
 
 // var db *sql.DB
 
 func main() {
+	var err error
+	nhlClient, err = nhlapi.FromEnv(context.TODO())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if nhlClient.Replaying() {
+		if err := ReplayGames(context.TODO()); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if os.Getenv("PROCESS_GAME_MODE") == "live" {
+		lambda.Start(LiveHandler)
+		return
+	}
 	lambda.Start(Handler)
 }
 
@@ -36,115 +60,251 @@ func main() {
 // 	Handler(ctx, sqsEvent)
 // }
 
-func Handler(ctx context.Context, sqsEvent events.SQSEvent) {
-	eventRecord := sqsEvent.Records[0]
+// Processes every game in the batch and reports only the messages that
+// failed with a retryable error so SQS redelivers those. Permanent failures
+// are dead-lettered with their reason.
+func Handler(ctx context.Context, sqsEvent events.SQSEvent) (events.SQSEventResponse, error) {
+	db, err := database.GetDatabase()
+	if err != nil {
+		return events.SQSEventResponse{}, err
+	}
+	response := events.SQSEventResponse{}
+
+	for _, eventRecord := range sqsEvent.Records {
+		err := processGameMessage(ctx, db, eventRecord.Body)
+		if err != nil && deadletter.ShouldRetry(ctx, eventRecord, err) {
+			response.BatchItemFailures = append(response.BatchItemFailures, events.SQSBatchItemFailure{ItemIdentifier: eventRecord.MessageId})
+		}
+	}
 
-	body := eventRecord.Body
+	return response, nil
+}
 
-	gamePk, err := strconv.Atoi(body)
+func processGameMessage(ctx context.Context, db *sql.DB, body string) error {
+	envelope, err := message.Parse(body, message.EntityGame)
 	if err != nil {
-		log.Fatal(err)
+		return failure.Permanent(err)
 	}
+	return processGameEnvelope(ctx, db, envelope)
+}
+
+func processGameEnvelope(ctx context.Context, db *sql.DB, envelope message.Envelope) error {
+	gamePk := envelope.Id
 
 	fmt.Println(fmt.Sprintf(" *** Processing GamePk %s ***", strconv.Itoa(gamePk)))
 
-	db := database.GetDatabase()
-	gameAlreadyProcessed := gameHasBeenProcessed(db, gamePk)
-	if gameAlreadyProcessed {
+	job, err := jobs.GetGameJob(db, gamePk)
+	if err != nil {
+		return err
+	}
+	if gameHasBeenProcessed(job, envelope.Force) {
 		fmt.Println(fmt.Sprintf("GamePk %s has already been processed", strconv.Itoa(gamePk)))
-		return
+		return nil
 	}
 
-	UpdateEtlGameStatus(db, gamePk, "IN_PROGRESS")
-
-	response, err := http.Get(fmt.Sprintf("https://statsapi.web.nhl.com/api/v1/game/%s/feed/live", strconv.Itoa(gamePk)))
-
+	startedAt := time.Now().UTC()
+	claimed, err := jobs.ClaimGameJob(db, gamePk, startedAt)
 	if err != nil {
-		log.Fatal(err)
+		return err
+	}
+	if !claimed {
+		return failure.Retryable(fmt.Errorf("gamePk %d is already being processed", gamePk))
 	}
 
-	responseData, err := ioutil.ReadAll(response.Body)
-
+	err = processGame(ctx, db, gamePk, startedAt)
 	if err != nil {
-		log.Fatal(err)
+		// Nothing from the run was committed, so the game goes back to FAILED
+		if failErr := jobs.FailGameJob(db, gamePk, startedAt, err); failErr != nil {
+			fmt.Println("Error recording failure:", failErr)
+		}
+		return err
 	}
+	return nil
+}
 
-	var responseObject GameResponse
-	json.Unmarshal(responseData, &responseObject)
-
-	onIceRecordList := GetPlayersOnIce(gamePk, responseObject.LiveData.Plays.AllPlays)
+// A complete game is only reprocessed when the message forces it. Games
+// held IN_PROGRESS are left to ClaimGameJob, which reclaims stale runs.
+func gameHasBeenProcessed(job jobs.GameJob, force bool) bool {
+	return job.Status == jobs.StatusComplete && !force
+}
 
-	tx, err := db.Begin()
-	println("Start Transation")
+// Loads a game and writes every table derived from it along with its
+// COMPLETE status in one unit of work
+func processGame(ctx context.Context, db *sql.DB, gamePk int, startedAt time.Time) error {
+	gameFeed, err := GetGameFeed(ctx, gamePk)
+	if err != nil {
+		return err
+	}
+	feed, shootoutAttemptList := SplitShootout(gamePk, gameFeed)
+	shifts, err := GetShiftChart(ctx, gamePk)
+	if err != nil {
+		return err
+	}
+	positions, err := GetPlayerPositions(db, feed, shifts)
+	if err != nil {
+		return err
+	}
+	onIceRecordList := GetPlayersOnIce(gamePk, feed, shifts, positions)
+	shiftRecordList := GetShiftRecords(gamePk, shifts, feed.Plays)
+	SetStrengthStates(feed, onIceRecordList)
+	SetNormalizedCoordinates(feed)
+	SetGameClock(feed)
+	shots := GetShots(feed)
+	boxscore, err := GetBoxscore(ctx, gamePk)
+	if err != nil {
+		return err
+	}
+	playerGameStatsList := GetPlayerGameStats(gamePk, boxscore, feed, shifts)
+	goalieGameStatsList := GetGoalieGameStats(gamePk, boxscore, feed, shifts)
+	faceoffRecordList, zoneStartRecordList := GetFaceoffRecords(gamePk, feed, shifts)
+	penaltyRecordList := GetPenaltyRecords(gamePk, feed)
 
-	// DeleteWithGamePk(tx, "play_by_play", gamePk)
-	// DeleteWithGamePk(tx, "play_by_play_contributor", gamePk)
-	// DeleteWithGamePk(tx, "play_by_play_on_ice", gamePk)
+	contentHash, err := hashContent(gameFeed, shifts, boxscore)
+	if err != nil {
+		return err
+	}
 
-	season, err := strconv.Atoi(responseObject.GameData.Game.Season)
+	xgModel, err := xg.CurrentModel()
 	if err != nil {
-		log.Fatal("Error parsing season string to int")
+		return err
 	}
 
-	DeleteWithGamePk(tx, "games", gamePk)
-	InsertGames(tx, responseObject.GameData)
-	InsertPlayByPlayRecords(tx, gamePk, responseObject.LiveData.Plays.AllPlays)
-	InsertOnIceRecords(tx, onIceRecordList)
-	InsertSkaterLineRecords(db, onIceRecordList, season)
+	season := int(feed.Game.Season)
 
-	UpdateEtlGameStatus(db, gamePk, "COMPLETE")
+	// The job is marked COMPLETE in the same transaction as the game's rows
+	return database.RunUnitOfWork(db, func(uow *database.UnitOfWork) error {
+		// Rows left by live mode or an earlier run may not match this feed,
+		// including runs from before the game had a job row
+		for _, tableName := range []string{"play_by_play", "play_by_play_contributor", "play_by_play_on_ice"} {
+			if err := DeleteWithGamePk(uow, tableName, gamePk); err != nil {
+				return err
+			}
+		}
 
-	err = tx.Commit()
-	if err != nil {
-		log.Fatal(err)
-	}
-	println("Committed Transation")
+		if err := DeleteWithGamePk(uow, "games", gamePk); err != nil {
+			return err
+		}
+		if err := InsertGames(uow, feed.Game); err != nil {
+			return err
+		}
+		if err := InsertPlayByPlayRecords(uow, gamePk, feed.Game.AwayTeamId, feed.Plays); err != nil {
+			return err
+		}
+		if err := InsertOnIceRecords(uow, onIceRecordList); err != nil {
+			return err
+		}
+		if err := DeleteWithGamePk(uow, "shifts", gamePk); err != nil {
+			return err
+		}
+		if err := InsertShiftRecords(uow, shiftRecordList); err != nil {
+			return err
+		}
+		if err := DeleteWithGamePk(uow, "shot_xg", gamePk); err != nil {
+			return err
+		}
+		if err := InsertShotXgRecords(uow, gamePk, shots, xgModel); err != nil {
+			return err
+		}
+		if err := DeleteWithGamePk(uow, "player_game_stats", gamePk); err != nil {
+			return err
+		}
+		if err := InsertPlayerGameStatsRecords(uow, playerGameStatsList); err != nil {
+			return err
+		}
+		if err := DeleteWithGamePk(uow, "goalie_game_stats", gamePk); err != nil {
+			return err
+		}
+		if err := InsertGoalieGameStatsRecords(uow, goalieGameStatsList); err != nil {
+			return err
+		}
+		if err := DeleteWithGamePk(uow, "faceoffs", gamePk); err != nil {
+			return err
+		}
+		if err := InsertFaceoffRecords(uow, faceoffRecordList); err != nil {
+			return err
+		}
+		if err := DeleteWithGamePk(uow, "faceoff_zone_starts", gamePk); err != nil {
+			return err
+		}
+		if err := InsertZoneStartRecords(uow, zoneStartRecordList); err != nil {
+			return err
+		}
+		if err := DeleteWithGamePk(uow, "penalties", gamePk); err != nil {
+			return err
+		}
+		if err := InsertPenaltyRecords(uow, penaltyRecordList); err != nil {
+			return err
+		}
+		if err := DeleteWithGamePk(uow, "shootout_attempts", gamePk); err != nil {
+			return err
+		}
+		if err := InsertShootoutAttemptRecords(uow, shootoutAttemptList); err != nil {
+			return err
+		}
+		if err := InsertSkaterLineRecords(uow, onIceRecordList, season); err != nil {
+			return err
+		}
+		if err := InsertForwardLineRecords(uow, onIceRecordList, season); err != nil {
+			return err
+		}
+		if err := InsertDefensePairRecords(uow, onIceRecordList, season); err != nil {
+			return err
+		}
+		// Built from the rows above, so it is read back inside the same unit
+		if err := aggregates.BuildPlayerGameOnIce(uow, gamePk); err != nil {
+			return err
+		}
 
+		return jobs.CompleteGameJob(uow, gamePk, startedAt, contentHash)
+	})
 }
 
-func DeleteWithGamePk(tx *sql.Tx, tableName string, gamePk int) {
-	result, err := tx.Exec(fmt.Sprintf("DELETE FROM %s PBP WHERE PBP.game_pk = %s", tableName, strconv.Itoa(gamePk)))
+func DeleteWithGamePk(uow *database.UnitOfWork, tableName string, gamePk int) error {
+	result, err := uow.Exec(fmt.Sprintf("DELETE FROM %s PBP WHERE PBP.game_pk = %s", tableName, strconv.Itoa(gamePk)))
 	if err != nil {
-		tx.Rollback()
-		log.Fatal(err)
+		return fmt.Errorf("failed to delete from %s for gamePk %d: %w", tableName, gamePk, err)
 	}
 
 	rows_affected, err := result.RowsAffected()
 	println(fmt.Sprintf("Delted %s records from %s for gamePk %s", strconv.Itoa(int(rows_affected)), tableName, strconv.Itoa(gamePk)))
+	return nil
 }
 
-func InsertGames(tx *sql.Tx, game GameData) {
+func InsertGames(uow *database.UnitOfWork, game model.Game) error {
 	stmt := fmt.Sprintf("INSERT INTO games VALUES (\"%s\", \"%s\", \"%s\", \"%s\", \"%s\", \"%s\")",
-		strconv.Itoa(game.Game.GamePk),
-		game.Game.Type,
-		game.Game.Season,
-		game.DateTime.DateTime,
-		strconv.Itoa(game.Teams.AwayTeam.Id),
-		strconv.Itoa(game.Teams.HomeTeam.Id),
+		strconv.Itoa(game.GamePk),
+		game.GameType,
+		game.Season,
+		game.StartTime,
+		strconv.Itoa(game.AwayTeamId),
+		strconv.Itoa(game.HomeTeamId),
 	)
 
 	println(stmt)
-	result, err := tx.Exec(stmt)
+	result, err := uow.Exec(stmt)
 
 	if err != nil {
-		tx.Rollback()
-		fmt.Println("Rolling back")
-		log.Fatal(err)
+		return fmt.Errorf("failed to insert into games: %w", err)
 	}
 
 	rows_affected, err := result.RowsAffected()
-	println(fmt.Sprintf("Inserted %s record into games for gamePk %s", strconv.Itoa(int(rows_affected)), strconv.Itoa(game.Game.GamePk)))
+	println(fmt.Sprintf("Inserted %s record into games for gamePk %s", strconv.Itoa(int(rows_affected)), strconv.Itoa(game.GamePk)))
+	return nil
 }
 
-func InsertPlayByPlayRecords(tx *sql.Tx, gamePk int, records []Play) {
+func InsertPlayByPlayRecords(uow *database.UnitOfWork, gamePk int, awayTeamId int, records []Play) error {
+	if len(records) == 0 {
+		return nil
+	}
+
 	playByPlayValueStrings := make([]string, 0, len(records))
-	playByPlayValueArgs := make([]interface{}, 0, len(records)*17)
+	playByPlayValueArgs := make([]interface{}, 0, len(records)*28)
 
 	contributorValueStrings := make([]string, 0, len(records))
 	contributorValueArgs := make([]interface{}, 0, len(records)*4)
 
 	for _, play := range records {
-		playByPlayValueStrings = append(playByPlayValueStrings, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
+		playByPlayValueStrings = append(playByPlayValueStrings, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
 		playByPlayValueArgs = append(playByPlayValueArgs,
 			gamePk,
 			play.About.EventIdx,
@@ -163,6 +323,17 @@ func InsertPlayByPlayRecords(tx *sql.Tx, gamePk int, records []Play) {
 			play.Coordinates.X,
 			play.Coordinates.Y,
 			play.Team.Id,
+			play.Strength.Label(play.Team.Id, awayTeamId),
+			play.Strength.EmptyNet(play.Team.Id, awayTeamId),
+			play.NormalizedCoordinates.X,
+			play.NormalizedCoordinates.Y,
+			play.ShotDistance,
+			play.ShotAngle,
+			play.GameSeconds,
+			play.PeriodSeconds,
+			play.EventTime,
+			play.AwayScoreState,
+			play.HomeScoreState,
 		)
 
 		for _, player := range play.Players {
@@ -177,57 +348,362 @@ func InsertPlayByPlayRecords(tx *sql.Tx, gamePk int, records []Play) {
 	}
 
 	stmt := fmt.Sprintf("INSERT INTO play_by_play VALUES %s ON DUPLICATE KEY UPDATE game_pk=game_pk", strings.Join(playByPlayValueStrings, ","))
-	result, err := tx.Exec(stmt, playByPlayValueArgs...)
+	result, err := uow.Exec(stmt, playByPlayValueArgs...)
 
 	if err != nil {
-		tx.Rollback()
-		log.Fatal(err)
+		return fmt.Errorf("failed to insert into play_by_play: %w", err)
 	}
 
 	rows_affected, err := result.RowsAffected()
 	println(fmt.Sprintf("Inserted %s records into play_by_play for gamePk %s", strconv.Itoa(int(rows_affected)), strconv.Itoa(gamePk)))
 
+	if len(contributorValueStrings) == 0 {
+		return nil
+	}
+
 	stmt = fmt.Sprintf("INSERT INTO play_by_play_contributor VALUES %s ON DUPLICATE KEY UPDATE game_pk=game_pk", strings.Join(contributorValueStrings, ","))
-	result, err = tx.Exec(stmt, contributorValueArgs...)
+	result, err = uow.Exec(stmt, contributorValueArgs...)
 
 	if err != nil {
-		tx.Rollback()
-		log.Fatal(err)
+		return fmt.Errorf("failed to insert into play_by_play_contributor: %w", err)
 	}
 
 	rows_affected, err = result.RowsAffected()
 	println(fmt.Sprintf("Inserted %s records into play_by_play_contributor for gamePk %s", strconv.Itoa(int(rows_affected)), strconv.Itoa(gamePk)))
+	return nil
 }
 
-func InsertOnIceRecords(tx *sql.Tx, records []OnIceRecord) {
+func InsertOnIceRecords(uow *database.UnitOfWork, records []OnIceRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
 	onIceValueStrings := make([]string, 0, len(records))
-	onIceValueArgs := make([]interface{}, 0, len(records)*17)
+	onIceValueArgs := make([]interface{}, 0, len(records)*7)
 
 	for _, oir := range records {
-		onIceValueStrings = append(onIceValueStrings, "(?, ?, ?, ?, ?)")
+		onIceValueStrings = append(onIceValueStrings, "(?, ?, ?, ?, ?, ?, ?)")
 		onIceValueArgs = append(onIceValueArgs,
 			oir.gamePk,
 			oir.teamId,
 			oir.eventIdx,
 			oir.lineHash,
 			oir.goalieId,
+			oir.forwardLineHash,
+			oir.defensePairHash,
 		)
 	}
 
 	stmt := fmt.Sprintf("INSERT INTO play_by_play_on_ice VALUES %s ON DUPLICATE KEY UPDATE game_pk=game_pk", strings.Join(onIceValueStrings, ","))
-	result, err := tx.Exec(stmt, onIceValueArgs...)
+	result, err := uow.Exec(stmt, onIceValueArgs...)
 
 	if err != nil {
-		tx.Rollback()
-		fmt.Println("Rolling back")
-		log.Fatal(err)
+		return fmt.Errorf("failed to insert into play_by_play_on_ice: %w", err)
 	}
 
 	rows_affected, err := result.RowsAffected()
 	println(fmt.Sprintf("Inserted %s records into play_by_play_on_ice for gamePk %s", strconv.Itoa(int(rows_affected)), strconv.Itoa(records[0].gamePk)))
+	return nil
+}
+
+func InsertShiftRecords(uow *database.UnitOfWork, records []ShiftRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	shiftValueStrings := make([]string, 0, len(records))
+	shiftValueArgs := make([]interface{}, 0, len(records)*9)
+
+	for _, sr := range records {
+		shiftValueStrings = append(shiftValueStrings, "(?, ?, ?, ?, ?, ?, ?, ?, ?)")
+		shiftValueArgs = append(shiftValueArgs,
+			sr.gamePk,
+			sr.playerId,
+			sr.teamId,
+			sr.period,
+			sr.shiftNumber,
+			sr.startSeconds,
+			sr.endSeconds,
+			sr.duration,
+			sr.endedWithGoal,
+		)
+	}
+
+	stmt := fmt.Sprintf("INSERT INTO shifts VALUES %s ON DUPLICATE KEY UPDATE game_pk=game_pk", strings.Join(shiftValueStrings, ","))
+	result, err := uow.Exec(stmt, shiftValueArgs...)
+
+	if err != nil {
+		return fmt.Errorf("failed to insert into shifts: %w", err)
+	}
+
+	rows_affected, err := result.RowsAffected()
+	println(fmt.Sprintf("Inserted %s records into shifts for gamePk %s", strconv.Itoa(int(rows_affected)), strconv.Itoa(records[0].gamePk)))
+	return nil
+}
+
+func InsertShotXgRecords(uow *database.UnitOfWork, gamePk int, shots []xg.Shot, xgModel xg.Model) error {
+	if len(shots) == 0 {
+		return nil
+	}
+
+	shotXgValueStrings := make([]string, 0, len(shots))
+	shotXgValueArgs := make([]interface{}, 0, len(shots)*11)
+
+	for _, shot := range shots {
+		shotXgValueStrings = append(shotXgValueStrings, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
+		shotXgValueArgs = append(shotXgValueArgs,
+			shot.GamePk,
+			shot.EventIdx,
+			shot.TeamId,
+			shot.Distance,
+			shot.Angle,
+			shot.ShotType,
+			shot.Rebound,
+			shot.Rush,
+			shot.StrengthState,
+			xgModel.Predict(shot),
+			xgModel.Version,
+		)
+	}
+
+	stmt := fmt.Sprintf("INSERT INTO shot_xg VALUES %s ON DUPLICATE KEY UPDATE game_pk=game_pk", strings.Join(shotXgValueStrings, ","))
+	result, err := uow.Exec(stmt, shotXgValueArgs...)
+
+	if err != nil {
+		return fmt.Errorf("failed to insert into shot_xg: %w", err)
+	}
+
+	rows_affected, err := result.RowsAffected()
+	println(fmt.Sprintf("Inserted %s records into shot_xg for gamePk %s", strconv.Itoa(int(rows_affected)), strconv.Itoa(gamePk)))
+	return nil
+}
+
+func InsertPlayerGameStatsRecords(uow *database.UnitOfWork, records []PlayerGameStatsRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	playerGameStatsValueStrings := make([]string, 0, len(records))
+	playerGameStatsValueArgs := make([]interface{}, 0, len(records)*19)
+
+	for _, pgs := range records {
+		playerGameStatsValueStrings = append(playerGameStatsValueStrings, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
+		playerGameStatsValueArgs = append(playerGameStatsValueArgs,
+			pgs.gamePk,
+			pgs.playerId,
+			pgs.teamId,
+			pgs.position,
+			pgs.goals,
+			pgs.assists,
+			pgs.shots,
+			pgs.hits,
+			pgs.blocks,
+			pgs.pim,
+			pgs.toiSeconds,
+			pgs.toiEvSeconds,
+			pgs.toiPpSeconds,
+			pgs.toiShSeconds,
+			pgs.faceoffWins,
+			pgs.faceoffLosses,
+			pgs.saves,
+			pgs.shotsAgainst,
+			pgs.decision,
+		)
+	}
+
+	stmt := fmt.Sprintf("INSERT INTO player_game_stats VALUES %s ON DUPLICATE KEY UPDATE game_pk=game_pk", strings.Join(playerGameStatsValueStrings, ","))
+	result, err := uow.Exec(stmt, playerGameStatsValueArgs...)
+
+	if err != nil {
+		return fmt.Errorf("failed to insert into player_game_stats: %w", err)
+	}
+
+	rows_affected, err := result.RowsAffected()
+	println(fmt.Sprintf("Inserted %s records into player_game_stats for gamePk %s", strconv.Itoa(int(rows_affected)), strconv.Itoa(records[0].gamePk)))
+	return nil
 }
 
-func InsertSkaterLineRecords(db *sql.DB, records []OnIceRecord, season int) {
+func InsertGoalieGameStatsRecords(uow *database.UnitOfWork, records []GoalieGameStatsRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	goalieGameStatsValueStrings := make([]string, 0, len(records))
+	goalieGameStatsValueArgs := make([]interface{}, 0, len(records)*10)
+
+	for _, ggs := range records {
+		goalieGameStatsValueStrings = append(goalieGameStatsValueStrings, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
+		goalieGameStatsValueArgs = append(goalieGameStatsValueArgs,
+			ggs.gamePk,
+			ggs.goalieId,
+			ggs.teamId,
+			ggs.splitType,
+			ggs.splitValue,
+			ggs.shotsAgainst,
+			ggs.saves,
+			ggs.goalsAgainst,
+			ggs.reboundsAllowed,
+			ggs.toiSeconds,
+		)
+	}
+
+	stmt := fmt.Sprintf("INSERT INTO goalie_game_stats VALUES %s ON DUPLICATE KEY UPDATE game_pk=game_pk", strings.Join(goalieGameStatsValueStrings, ","))
+	result, err := uow.Exec(stmt, goalieGameStatsValueArgs...)
+
+	if err != nil {
+		return fmt.Errorf("failed to insert into goalie_game_stats: %w", err)
+	}
+
+	rows_affected, err := result.RowsAffected()
+	println(fmt.Sprintf("Inserted %s records into goalie_game_stats for gamePk %s", strconv.Itoa(int(rows_affected)), strconv.Itoa(records[0].gamePk)))
+	return nil
+}
+
+func InsertFaceoffRecords(uow *database.UnitOfWork, records []FaceoffRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	faceoffValueStrings := make([]string, 0, len(records))
+	faceoffValueArgs := make([]interface{}, 0, len(records)*11)
+
+	for _, fr := range records {
+		faceoffValueStrings = append(faceoffValueStrings, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
+		faceoffValueArgs = append(faceoffValueArgs,
+			fr.gamePk,
+			fr.eventIdx,
+			fr.period,
+			fr.periodSeconds,
+			fr.winnerId,
+			fr.winnerTeamId,
+			fr.loserId,
+			fr.loserTeamId,
+			fr.awayZone,
+			fr.homeZone,
+			fr.strengthState,
+		)
+	}
+
+	stmt := fmt.Sprintf("INSERT INTO faceoffs VALUES %s ON DUPLICATE KEY UPDATE game_pk=game_pk", strings.Join(faceoffValueStrings, ","))
+	result, err := uow.Exec(stmt, faceoffValueArgs...)
+
+	if err != nil {
+		return fmt.Errorf("failed to insert into faceoffs: %w", err)
+	}
+
+	rows_affected, err := result.RowsAffected()
+	println(fmt.Sprintf("Inserted %s records into faceoffs for gamePk %s", strconv.Itoa(int(rows_affected)), strconv.Itoa(records[0].gamePk)))
+	return nil
+}
+
+func InsertZoneStartRecords(uow *database.UnitOfWork, records []ZoneStartRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	zoneStartValueStrings := make([]string, 0, len(records))
+	zoneStartValueArgs := make([]interface{}, 0, len(records)*5)
+
+	for _, zsr := range records {
+		zoneStartValueStrings = append(zoneStartValueStrings, "(?, ?, ?, ?, ?)")
+		zoneStartValueArgs = append(zoneStartValueArgs,
+			zsr.gamePk,
+			zsr.eventIdx,
+			zsr.playerId,
+			zsr.teamId,
+			zsr.zone,
+		)
+	}
+
+	stmt := fmt.Sprintf("INSERT INTO faceoff_zone_starts VALUES %s ON DUPLICATE KEY UPDATE game_pk=game_pk", strings.Join(zoneStartValueStrings, ","))
+	result, err := uow.Exec(stmt, zoneStartValueArgs...)
+
+	if err != nil {
+		return fmt.Errorf("failed to insert into faceoff_zone_starts: %w", err)
+	}
+
+	rows_affected, err := result.RowsAffected()
+	println(fmt.Sprintf("Inserted %s records into faceoff_zone_starts for gamePk %s", strconv.Itoa(int(rows_affected)), strconv.Itoa(records[0].gamePk)))
+	return nil
+}
+
+func InsertPenaltyRecords(uow *database.UnitOfWork, records []PenaltyRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	penaltyValueStrings := make([]string, 0, len(records))
+	penaltyValueArgs := make([]interface{}, 0, len(records)*12)
+
+	for _, pr := range records {
+		penaltyValueStrings = append(penaltyValueStrings, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
+		penaltyValueArgs = append(penaltyValueArgs,
+			pr.gamePk,
+			pr.eventIdx,
+			pr.teamId,
+			pr.penalizedPlayerId,
+			pr.servedByPlayerId,
+			pr.drawnByPlayerId,
+			pr.infraction,
+			pr.severity,
+			pr.minutes,
+			pr.startGameSeconds,
+			pr.endGameSeconds,
+			pr.endedByGoal,
+		)
+	}
+
+	stmt := fmt.Sprintf("INSERT INTO penalties VALUES %s ON DUPLICATE KEY UPDATE game_pk=game_pk", strings.Join(penaltyValueStrings, ","))
+	result, err := uow.Exec(stmt, penaltyValueArgs...)
+
+	if err != nil {
+		return fmt.Errorf("failed to insert into penalties: %w", err)
+	}
+
+	rows_affected, err := result.RowsAffected()
+	println(fmt.Sprintf("Inserted %s records into penalties for gamePk %s", strconv.Itoa(int(rows_affected)), strconv.Itoa(records[0].gamePk)))
+	return nil
+}
+
+func InsertShootoutAttemptRecords(uow *database.UnitOfWork, records []ShootoutAttemptRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	shootoutValueStrings := make([]string, 0, len(records))
+	shootoutValueArgs := make([]interface{}, 0, len(records)*8)
+
+	for _, sar := range records {
+		shootoutValueStrings = append(shootoutValueStrings, "(?, ?, ?, ?, ?, ?, ?, ?)")
+		shootoutValueArgs = append(shootoutValueArgs,
+			sar.gamePk,
+			sar.eventIdx,
+			sar.round,
+			sar.teamId,
+			sar.shooterId,
+			sar.goalieId,
+			sar.result,
+			sar.shotType,
+		)
+	}
+
+	stmt := fmt.Sprintf("INSERT INTO shootout_attempts VALUES %s ON DUPLICATE KEY UPDATE game_pk=game_pk", strings.Join(shootoutValueStrings, ","))
+	result, err := uow.Exec(stmt, shootoutValueArgs...)
+
+	if err != nil {
+		return fmt.Errorf("failed to insert into shootout_attempts: %w", err)
+	}
+
+	rows_affected, err := result.RowsAffected()
+	println(fmt.Sprintf("Inserted %s records into shootout_attempts for gamePk %s", strconv.Itoa(int(rows_affected)), strconv.Itoa(records[0].gamePk)))
+	return nil
+}
+
+func InsertSkaterLineRecords(uow *database.UnitOfWork, records []OnIceRecord, season int) error {
+	if len(records) == 0 {
+		return nil
+	}
+
 	stakerLineValueStrings := make([]string, 0, len(records))
 	stakerLineValueArgs := make([]interface{}, 0, len(records)*17)
 
@@ -247,46 +723,79 @@ func InsertSkaterLineRecords(db *sql.DB, records []OnIceRecord, season int) {
 	}
 
 	stmt := fmt.Sprintf("INSERT INTO team_season_skater_lines VALUES %s ON DUPLICATE KEY UPDATE line_hash=line_hash", strings.Join(stakerLineValueStrings, ","))
-	result, err := db.Exec(stmt, stakerLineValueArgs...)
+	result, err := uow.Exec(stmt, stakerLineValueArgs...)
 
 	if err != nil {
-		fmt.Println("Error inserting staker line record")
-		log.Fatal(err)
+		return fmt.Errorf("failed to insert into team_season_skater_lines: %w", err)
 	}
 
 	rows_affected, err := result.RowsAffected()
 	println(fmt.Sprintf("Inserted %s records into team_season_skater_lines for gamePk %s", strconv.Itoa(int(rows_affected)), strconv.Itoa(records[0].gamePk)))
+	return nil
 }
 
-func UpdateEtlGameStatus(db *sql.DB, gamePk int, status string) {
-	stmt := fmt.Sprintf("INSERT INTO etl_game_status VALUES (\"%s\", \"%s\") ON DUPLICATE KEY UPDATE status=\"%s\"", strconv.Itoa(gamePk), status, status)
-	result, err := db.Exec(stmt)
+func InsertForwardLineRecords(uow *database.UnitOfWork, records []OnIceRecord, season int) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	forwardLineValueStrings := make([]string, 0, len(records))
+	forwardLineValueArgs := make([]interface{}, 0, len(records)*8)
+
+	for _, oir := range records {
+		if oir.forwardLineHash == "" {
+			continue
+		}
+		forwardLineValueStrings = append(forwardLineValueStrings, "(?, ?, ?, ?, ?, ?, ?, ?)")
+		forwardLineValueArgs = append(forwardLineValueArgs, season, oir.teamId, oir.forwardLineHash)
+		forwardLineValueArgs = append(forwardLineValueArgs, padIds(oir.forwardIds, forwardLineSize)...)
+	}
+
+	if len(forwardLineValueStrings) == 0 {
+		return nil
+	}
+
+	stmt := fmt.Sprintf("INSERT INTO team_season_forward_lines VALUES %s ON DUPLICATE KEY UPDATE forward_line_hash=forward_line_hash", strings.Join(forwardLineValueStrings, ","))
+	result, err := uow.Exec(stmt, forwardLineValueArgs...)
 
 	if err != nil {
-		fmt.Println("Error updating ETL Game Status")
-		log.Fatal(err)
+		return fmt.Errorf("failed to insert into team_season_forward_lines: %w", err)
 	}
 
 	rows_affected, err := result.RowsAffected()
-	println(fmt.Sprintf("Inserted %s records into etl_game_status for gamePk %s", strconv.Itoa(int(rows_affected)), strconv.Itoa(gamePk)))
+	println(fmt.Sprintf("Inserted %s records into team_season_forward_lines for gamePk %s", strconv.Itoa(int(rows_affected)), strconv.Itoa(records[0].gamePk)))
+	return nil
 }
 
-func gameHasBeenProcessed(db *sql.DB, gamePk int) bool {
-	query := fmt.Sprintf("SELECT status FROM etl_game_status WHERE game_pk = %s", strconv.Itoa(gamePk))
-	results, err := db.Query(query)
-
-	if err != nil {
-		log.Fatal("Error retrieving etl game status")
+func InsertDefensePairRecords(uow *database.UnitOfWork, records []OnIceRecord, season int) error {
+	if len(records) == 0 {
+		return nil
 	}
 
-	gameStatus := ""
+	defensePairValueStrings := make([]string, 0, len(records))
+	defensePairValueArgs := make([]interface{}, 0, len(records)*6)
 
-	for results.Next() {
-		err = results.Scan(&gameStatus)
-		if err != nil {
-			log.Fatal(err)
+	for _, oir := range records {
+		if oir.defensePairHash == "" {
+			continue
 		}
+		defensePairValueStrings = append(defensePairValueStrings, "(?, ?, ?, ?, ?, ?)")
+		defensePairValueArgs = append(defensePairValueArgs, season, oir.teamId, oir.defensePairHash)
+		defensePairValueArgs = append(defensePairValueArgs, padIds(oir.defenseIds, defensePairSize)...)
+	}
+
+	if len(defensePairValueStrings) == 0 {
+		return nil
+	}
+
+	stmt := fmt.Sprintf("INSERT INTO team_season_defense_pairs VALUES %s ON DUPLICATE KEY UPDATE defense_pair_hash=defense_pair_hash", strings.Join(defensePairValueStrings, ","))
+	result, err := uow.Exec(stmt, defensePairValueArgs...)
+
+	if err != nil {
+		return fmt.Errorf("failed to insert into team_season_defense_pairs: %w", err)
 	}
 
-	return gameStatus == "COMPLETE"
+	rows_affected, err := result.RowsAffected()
+	println(fmt.Sprintf("Inserted %s records into team_season_defense_pairs for gamePk %s", strconv.Itoa(int(rows_affected)), strconv.Itoa(records[0].gamePk)))
+	return nil
 }
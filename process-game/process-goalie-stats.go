@@ -0,0 +1,128 @@
+package main
+
+import (
+	"github.com/gavswe19/ice-pipelines/model"
+	"github.com/gavswe19/ice-pipelines/xg"
+)
+
+type goalieSplitKey struct {
+	goalieId   int
+	splitType  string
+	splitValue string
+}
+
+// Builds save and goals against splits for every goalie in the boxscore.
+// Each goalie gets an "all" row plus one row per strength, shot type and
+// danger zone they faced.
+func GetGoalieGameStats(gamePk int, boxscore model.BoxscoreResponse, feed GameFeed, shifts []model.Shift) []GoalieGameStatsRecord {
+	awayTeamId := feed.Game.AwayTeamId
+	homeTeamId := feed.Game.HomeTeamId
+
+	splits := make(map[goalieSplitKey]*GoalieGameStatsRecord)
+	split := func(goalieId int, teamId int, splitType string, splitValue string) *GoalieGameStatsRecord {
+		key := goalieSplitKey{goalieId, splitType, splitValue}
+		record, ok := splits[key]
+		if !ok {
+			record = &GoalieGameStatsRecord{
+				gamePk:     gamePk,
+				goalieId:   goalieId,
+				teamId:     teamId,
+				splitType:  splitType,
+				splitValue: splitValue,
+			}
+			splits[key] = record
+		}
+		return record
+	}
+
+	goalieTeams := make(map[int]int)
+	for _, goalie := range boxscore.PlayerByGameStats.AwayTeam.Goalies {
+		goalieTeams[goalie.PlayerId] = awayTeamId
+	}
+	for _, goalie := range boxscore.PlayerByGameStats.HomeTeam.Goalies {
+		goalieTeams[goalie.PlayerId] = homeTeamId
+	}
+
+	toi := make(map[int]int)
+	for _, shift := range shifts {
+		if _, ok := goalieTeams[shift.PlayerId]; ok {
+			toi[shift.PlayerId] += model.PeriodTimeToSeconds(shift.EndTime) - model.PeriodTimeToSeconds(shift.StartTime)
+		}
+	}
+
+	for goalieId, teamId := range goalieTeams {
+		split(goalieId, teamId, "all", "all")
+	}
+
+	for i, play := range feed.Plays {
+		eventTypeId := play.Result.EventTypeId
+		if eventTypeId != "SHOT" && eventTypeId != "GOAL" {
+			continue
+		}
+
+		goalieId := playGoalieId(play)
+		teamId, ok := goalieTeams[goalieId]
+		if !ok {
+			continue
+		}
+
+		isGoal := eventTypeId == "GOAL"
+		rebound := !isGoal && allowedRebound(play, feed.Plays[i+1:])
+
+		x := float64(play.NormalizedCoordinates.X)
+		y := float64(play.NormalizedCoordinates.Y)
+		for _, splitKey := range [][2]string{
+			{"all", "all"},
+			{"strength", play.Strength.Label(teamId, awayTeamId)},
+			{"shot_type", xg.NormalizeShotType(play.Result.SecondaryType)},
+			{"danger_zone", xg.DangerZone(x, y)},
+		} {
+			record := split(goalieId, teamId, splitKey[0], splitKey[1])
+			record.shotsAgainst++
+			if isGoal {
+				record.goalsAgainst++
+			} else {
+				record.saves++
+			}
+			if rebound {
+				record.reboundsAllowed++
+			}
+		}
+	}
+
+	records := make([]GoalieGameStatsRecord, 0, len(splits))
+	for _, record := range splits {
+		record.toiSeconds = toi[record.goalieId]
+		records = append(records, *record)
+	}
+
+	return records
+}
+
+func playGoalieId(play Play) int {
+	for _, player := range play.Players {
+		if player.PlayerType == "Goalie" {
+			return player.Player.PlayerId
+		}
+	}
+	return 0
+}
+
+// A save is a rebound allowed when the shooting team gets another unblocked
+// attempt away within a few seconds in the same period
+func allowedRebound(save Play, following []Play) bool {
+	saveSeconds := model.PeriodTimeToSeconds(save.About.PeriodTime)
+
+	for _, play := range following {
+		if play.About.Period != save.About.Period || model.PeriodTimeToSeconds(play.About.PeriodTime)-saveSeconds > xg.ReboundSeconds {
+			return false
+		}
+
+		switch play.Result.EventTypeId {
+		case "SHOT", "MISSED_SHOT", "GOAL":
+			return play.Team.Id == save.Team.Id
+		}
+	}
+
+	return false
+}
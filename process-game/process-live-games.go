@@ -0,0 +1,193 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/gavswe19/ice-pipelines/database"
+	"github.com/gavswe19/ice-pipelines/jobs"
+	"github.com/gavswe19/ice-pipelines/message"
+	"github.com/gavswe19/ice-pipelines/model"
+)
+
+const gameQueueUrl = "https://sqs.us-east-1.amazonaws.com/271463937680/ice-game-queue"
+
+// Polls today's games on a schedule. Games in progress have their new plays
+// added and are marked LIVE; once a LIVE game is final it is queued for
+// the full pipeline and marked FINAL. A game that fails does not stop the
+// others from being polled.
+func LiveHandler(ctx context.Context) error {
+	db, err := database.GetDatabase()
+	if err != nil {
+		return err
+	}
+
+	scoreGames, err := getScoreGames(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, game := range scoreGames {
+		var err error
+		switch game.GameState {
+		case "LIVE", "CRIT":
+			err = processLiveGame(ctx, db, game.Id)
+		case "FINAL", "OFF":
+			err = queueFinalGameIfLive(ctx, db, game.Id)
+		}
+
+		if err != nil {
+			fmt.Println(fmt.Sprintf("Error polling GamePk %s: %s", strconv.Itoa(game.Id), err))
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func processLiveGame(ctx context.Context, db *sql.DB, gamePk int) error {
+	fmt.Println(fmt.Sprintf(" *** Polling live GamePk %s ***", strconv.Itoa(gamePk)))
+
+	gameFeed, err := GetGameFeed(ctx, gamePk)
+	if err != nil {
+		return err
+	}
+	feed, _ := SplitShootout(gamePk, gameFeed)
+	shifts, err := GetShiftChart(ctx, gamePk)
+	if err != nil {
+		return err
+	}
+	positions, err := GetPlayerPositions(db, feed, shifts)
+	if err != nil {
+		return err
+	}
+	onIceRecordList := GetPlayersOnIce(gamePk, feed, shifts, positions)
+	SetStrengthStates(feed, onIceRecordList)
+	SetNormalizedCoordinates(feed)
+	SetGameClock(feed)
+
+	season := int(feed.Game.Season)
+
+	return database.RunUnitOfWork(db, func(uow *database.UnitOfWork) error {
+		// A game process-game has claimed or finished is left to it
+		live, err := jobs.TransitionGameJobStatus(uow, gamePk, []string{"", jobs.StatusLive}, jobs.StatusLive)
+		if err != nil || !live {
+			return err
+		}
+
+		lastEventIdx, err := getLastEventIdx(uow, gamePk)
+		if err != nil {
+			return err
+		}
+		if lastEventIdx == -1 {
+			if err := DeleteWithGamePk(uow, "games", gamePk); err != nil {
+				return err
+			}
+			if err := InsertGames(uow, feed.Game); err != nil {
+				return err
+			}
+		}
+
+		// Only plays after the last one stored are added, with their lineups.
+		// Plays corrected or removed during the game, and lineups the shift
+		// chart had not caught up to, are fixed when process-game reloads it.
+		newPlays := make([]Play, 0)
+		for _, play := range feed.Plays {
+			if play.About.EventIdx > lastEventIdx {
+				newPlays = append(newPlays, play)
+			}
+		}
+		newOnIceRecords := make([]OnIceRecord, 0)
+		for _, oir := range onIceRecordList {
+			if oir.eventIdx > lastEventIdx {
+				newOnIceRecords = append(newOnIceRecords, oir)
+			}
+		}
+
+		if err := InsertPlayByPlayRecords(uow, gamePk, feed.Game.AwayTeamId, newPlays); err != nil {
+			return err
+		}
+		if err := InsertOnIceRecords(uow, newOnIceRecords); err != nil {
+			return err
+		}
+		if err := InsertSkaterLineRecords(uow, newOnIceRecords, season); err != nil {
+			return err
+		}
+		if err := InsertForwardLineRecords(uow, newOnIceRecords, season); err != nil {
+			return err
+		}
+		return InsertDefensePairRecords(uow, newOnIceRecords, season)
+	})
+}
+
+// Returns -1 when none of the game's plays are stored yet
+func getLastEventIdx(q database.Queryer, gamePk int) (int, error) {
+	rows, err := q.Query("SELECT MAX(event_idx) FROM play_by_play WHERE game_pk = ?", gamePk)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query play_by_play for gamePk %d: %w", gamePk, err)
+	}
+	defer rows.Close()
+
+	var lastEventIdx sql.NullInt64
+	if rows.Next() {
+		if err := rows.Scan(&lastEventIdx); err != nil {
+			return 0, fmt.Errorf("failed to scan play_by_play for gamePk %d: %w", gamePk, err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	if !lastEventIdx.Valid {
+		return -1, nil
+	}
+	return int(lastEventIdx.Int64), nil
+}
+
+func queueFinalGameIfLive(ctx context.Context, db *sql.DB, gamePk int) error {
+	job, err := jobs.GetGameJob(db, gamePk)
+	if err != nil || job.Status != jobs.StatusLive {
+		return err
+	}
+	return queueFinalGame(ctx, db, gamePk)
+}
+
+func queueFinalGame(ctx context.Context, db *sql.DB, gamePk int) error {
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	body, err := message.New(message.EntityGame, gamePk, "process-live-games").Encode()
+	if err != nil {
+		return err
+	}
+
+	queueMessage := &sqs.SendMessageInput{
+		MessageBody: aws.String(body),
+		QueueUrl:    aws.String(gameQueueUrl),
+	}
+	_, err = sqs.NewFromConfig(cfg).SendMessage(ctx, queueMessage)
+
+	if err != nil {
+		return err
+	}
+
+	// process-game may already have claimed or finished the game, so only a
+	// game still LIVE is marked FINAL. A failure here queues it again on the
+	// next poll, which process-game skips once the game is COMPLETE.
+	_, err = jobs.TransitionGameJobStatus(db, gamePk, []string{jobs.StatusLive}, jobs.StatusFinal)
+	return err
+}
+
+func getScoreGames(ctx context.Context) ([]model.ScoreGame, error) {
+	scoreNow, err := nhlClient.ScoreNow(ctx)
+	return scoreNow.Games, err
+}
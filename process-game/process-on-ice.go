@@ -1,82 +1,110 @@
 package main
 
 import (
-	"crypto/md5"
-	"encoding/hex"
-	"encoding/json"
-	"fmt"
-	"io/ioutil"
-	"log"
-	"net/http"
+	"context"
 	"sort"
-	"strconv"
-	"strings"
 
+	"github.com/gavswe19/ice-pipelines/aggregates"
+	"github.com/gavswe19/ice-pipelines/model"
 	"golang.org/x/exp/slices"
 )
 
-type OnIcePlayResult struct {
-	awayLineHash OnIceRecord
-	homeLineHash OnIceRecord
+type shiftInterval struct {
+	playerId int
+	start    int
+	end      int
 }
 
-func GetPlayersOnIce(gamePk int, records []Play) []OnIceRecord {
-	outChannel := make(chan OnIceRecord)
+// Shift intervals for one team, keyed by period
+type teamTimeline struct {
+	teamId  int
+	periods map[int][]shiftInterval
+}
 
-	for _, play := range records {
-		timeStamp := formatTimeStamp(play.About.DateTime)
-		go processOnIce(gamePk, play.About.EventIdx, timeStamp, outChannel)
+func GetShiftChart(ctx context.Context, gamePk int) ([]model.Shift, error) {
+	shiftChart, err := nhlClient.ShiftCharts(ctx, gamePk)
+	if err != nil {
+		return nil, err
 	}
+	return shiftChart.Shifts(), nil
+}
 
-	var onIceRecordList []OnIceRecord
+// Resolves the skaters and goalie each team had on the ice for every play
+// from the game's shift chart. Emits an away and a home record per play.
+func GetPlayersOnIce(gamePk int, feed GameFeed, shifts []model.Shift, positions map[int]string) []OnIceRecord {
+	awayTimeline := buildTeamTimeline(feed.Game.AwayTeamId, shifts)
+	homeTimeline := buildTeamTimeline(feed.Game.HomeTeamId, shifts)
 
-	for i := 0; i < len(records)*2; i++ {
-		onIceRecordList = append(onIceRecordList, <-outChannel)
-		// fmt.Println(<-outChannel)
+	onIceRecordList := make([]OnIceRecord, 0, len(feed.Plays)*2)
+
+	for _, play := range feed.Plays {
+		seconds := model.PeriodTimeToSeconds(play.About.PeriodTime)
+		isFaceoff := play.Result.EventTypeId == "FACEOFF"
+
+		for _, timeline := range []teamTimeline{awayTimeline, homeTimeline} {
+			playerIdList := timeline.playersOnIce(play.About.Period, seconds, isFaceoff)
+			onIceRecordList = append(onIceRecordList, getTeamLineHash(gamePk, play.About.EventIdx, timeline.teamId, playerIdList, feed.Goalies, positions))
+		}
 	}
 
 	return onIceRecordList
 }
 
-func processOnIce(gamePk int, eventIdx int, timeStamp string, outChannel chan<- OnIceRecord) {
-	response, err := http.Get(fmt.Sprintf("https://statsapi.web.nhl.com/api/v1/game/%s/feed/live?timecode=%s", strconv.Itoa(gamePk), timeStamp))
+func buildTeamTimeline(teamId int, shifts []model.Shift) teamTimeline {
+	timeline := teamTimeline{
+		teamId:  teamId,
+		periods: map[int][]shiftInterval{},
+	}
 
-	if err != nil {
-		log.Fatal(err)
+	for _, shift := range shifts {
+		if shift.TeamId != teamId {
+			continue
+		}
+		timeline.periods[shift.Period] = append(timeline.periods[shift.Period], shiftInterval{
+			playerId: shift.PlayerId,
+			start:    model.PeriodTimeToSeconds(shift.StartTime),
+			end:      model.PeriodTimeToSeconds(shift.EndTime),
+		})
 	}
 
-	responseData, err := ioutil.ReadAll(response.Body)
+	return timeline
+}
 
-	if err != nil {
-		log.Fatal(err)
-	}
+// Players on a shift change boundary are assigned to the shift that is
+// starting for faceoffs and to the shift that is ending for everything else.
+func (t teamTimeline) playersOnIce(period int, seconds int, isFaceoff bool) []int {
+	playerIdList := make([]int, 0, 6)
 
-	var responseObject GameResponse
-	json.Unmarshal(responseData, &responseObject)
+	for _, interval := range t.periods[period] {
+		var onIce bool
+		if isFaceoff || seconds == 0 {
+			onIce = interval.start <= seconds && seconds < interval.end
+		} else {
+			onIce = interval.start < seconds && seconds <= interval.end
+		}
 
-	awayLineHash := getTeamLineHash(gamePk, eventIdx, responseObject.LiveData.Boxscore.BoxscoreTeams.BoxscoreTeamAway)
-	homeLineHash := getTeamLineHash(gamePk, eventIdx, responseObject.LiveData.Boxscore.BoxscoreTeams.BoxscoreTeamHome)
+		if onIce && !slices.Contains(playerIdList, interval.playerId) {
+			playerIdList = append(playerIdList, interval.playerId)
+		}
+	}
 
-	outChannel <- awayLineHash
-	outChannel <- homeLineHash
+	return playerIdList
 }
 
-func getTeamLineHash(gamePk int, eventIdx int, boxScoreTeam BoxscoreTeam) OnIceRecord {
-	onIcePlus := boxScoreTeam.OnIcePlus
-
+func getTeamLineHash(gamePk int, eventIdx int, teamId int, onIcePlayerIds []int, goalies []int, positions map[int]string) OnIceRecord {
 	onIceRecord := OnIceRecord{
 		gamePk:   gamePk,
-		teamId:   boxScoreTeam.Team.Id,
+		teamId:   teamId,
 		eventIdx: eventIdx,
 	}
 
 	playerIdList := make([]int, 0, 6)
-	for _, onIcePlayer := range onIcePlus {
-		if slices.Contains(boxScoreTeam.Goalies, onIcePlayer.PlayerId) {
-			onIceRecord.goalieId = onIcePlayer.PlayerId
+	for _, playerId := range onIcePlayerIds {
+		if slices.Contains(goalies, playerId) || positions[playerId] == "G" {
+			onIceRecord.goalieId = playerId
 			continue
 		}
-		playerIdList = append(playerIdList, onIcePlayer.PlayerId)
+		playerIdList = append(playerIdList, playerId)
 	}
 	sort.Ints(playerIdList)
 
@@ -97,22 +125,23 @@ func getTeamLineHash(gamePk int, eventIdx int, boxScoreTeam BoxscoreTeam) OnIceR
 		}
 	}
 
-	lineStr := strings.Trim(strings.Join(strings.Fields(fmt.Sprint(playerIdList)), "-"), "[]")
-	lineHash := getLineHash(lineStr)
-	onIceRecord.lineHash = lineHash
+	onIceRecord.lineHash = aggregates.LineHash(playerIdList)
 
-	return onIceRecord
-}
+	forwardIds, defenseIds := splitSkaters(playerIdList, positions)
+	if fitsRegistry(gamePk, eventIdx, teamId, "forward line", forwardIds, forwardLineSize) {
+		onIceRecord.forwardIds = forwardIds
+		onIceRecord.forwardLineHash = aggregates.LineHash(forwardIds)
+	}
+	if fitsRegistry(gamePk, eventIdx, teamId, "defense pair", defenseIds, defensePairSize) {
+		onIceRecord.defenseIds = defenseIds
+		onIceRecord.defensePairHash = aggregates.LineHash(defenseIds)
+	}
 
-func getLineHash(line string) string {
-	hash := md5.Sum([]byte(line))
-	return hex.EncodeToString(hash[:])
+	return onIceRecord
 }
 
-func formatTimeStamp(timeStamp string) string {
-	timeStamp = strings.ReplaceAll(timeStamp, "-", "")
-	timeStamp = strings.ReplaceAll(timeStamp, ":", "")
-	timeStamp = strings.ReplaceAll(timeStamp, "T", "_")
-	timeStamp = strings.ReplaceAll(timeStamp, "Z", "")
-	return timeStamp
+// Seconds elapsed since the opening faceoff. Every period before the current
+// one, overtime included, is counted as a full 20 minutes.
+func gameSeconds(period int, periodTime string) int {
+	return (period-1)*20*60 + model.PeriodTimeToSeconds(periodTime)
 }
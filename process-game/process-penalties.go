@@ -0,0 +1,100 @@
+package main
+
+// Builds a penalty record for every penalty call. The end time is the start
+// plus the penalty minutes, cut short when a minor is ended by a power-play
+// goal against the penalized team.
+func GetPenaltyRecords(gamePk int, feed GameFeed) []PenaltyRecord {
+	awayTeamId := feed.Game.AwayTeamId
+	var penaltyRecordList []PenaltyRecord
+
+	for _, play := range feed.Plays {
+		switch play.Result.EventTypeId {
+		case "PENALTY":
+			penalty := PenaltyRecord{
+				gamePk:           gamePk,
+				eventIdx:         play.About.EventIdx,
+				teamId:           play.Team.Id,
+				infraction:       play.Result.SecondaryType,
+				severity:         play.Result.PenaltySeverity,
+				minutes:          play.Result.PenaltyMinutes,
+				startGameSeconds: gameSeconds(play.About.Period, play.About.PeriodTime),
+			}
+			penalty.endGameSeconds = penalty.startGameSeconds + penalty.minutes*60
+
+			for _, player := range play.Players {
+				switch player.PlayerType {
+				case "PenaltyOn":
+					penalty.penalizedPlayerId = player.Player.PlayerId
+				case "DrewBy":
+					penalty.drawnByPlayerId = player.Player.PlayerId
+				case "ServedBy":
+					penalty.servedByPlayerId = player.Player.PlayerId
+				}
+			}
+			penaltyRecordList = append(penaltyRecordList, penalty)
+
+		case "GOAL":
+			if play.Strength.advantage(play.Team.Id == awayTeamId) != 1 {
+				continue
+			}
+			endPenaltyOnGoal(penaltyRecordList, play.Team.Id, gameSeconds(play.About.Period, play.About.PeriodTime))
+		}
+	}
+
+	return penaltyRecordList
+}
+
+// A power-play goal releases the penalized team's minor that would expire
+// first. A double minor only loses its current two minutes. Coincidental
+// minors are never released.
+func endPenaltyOnGoal(penalties []PenaltyRecord, scoringTeamId int, goalSeconds int) {
+	ending := -1
+	for i, penalty := range penalties {
+		if penalty.teamId == scoringTeamId || !penalty.isMinor() || isCoincidental(penalties, i) {
+			continue
+		}
+		if penalty.startGameSeconds >= goalSeconds || penalty.endGameSeconds <= goalSeconds {
+			continue
+		}
+		if ending == -1 || penalty.endGameSeconds < penalties[ending].endGameSeconds {
+			ending = i
+		}
+	}
+
+	if ending == -1 {
+		return
+	}
+
+	penalty := &penalties[ending]
+	// More than two minutes left means the double minor's first half is
+	// still being served, including after an earlier goal restarted it
+	if penalty.minutes == 4 && penalty.endGameSeconds-goalSeconds > 2*60 {
+		penalty.endGameSeconds = goalSeconds + 2*60
+	} else {
+		penalty.endGameSeconds = goalSeconds
+	}
+	penalty.endedByGoal = true
+}
+
+func (p PenaltyRecord) isMinor() bool {
+	return (p.severity == "Minor" || p.severity == "Bench Minor") && p.minutes > 0 && p.minutes <= 4
+}
+
+// Whether a minor is offset by one the other team took at the same time for
+// the same length. Penalties are paired off in order, so only as many of a
+// team's minors are coincidental as the other team has matching ones.
+func isCoincidental(penalties []PenaltyRecord, i int) bool {
+	penalty := penalties[i]
+	rank, opposing := 0, 0
+	for j, other := range penalties {
+		if !other.isMinor() || other.startGameSeconds != penalty.startGameSeconds || other.minutes != penalty.minutes {
+			continue
+		}
+		if other.teamId != penalty.teamId {
+			opposing++
+		} else if j < i {
+			rank++
+		}
+	}
+	return rank < opposing
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/gavswe19/ice-pipelines/model"
+)
+
+const (
+	scoringTeamId   = 1
+	penalizedTeamId = 2
+)
+
+func minor(teamId int, start int) PenaltyRecord {
+	return PenaltyRecord{teamId: teamId, severity: "Minor", minutes: 2, startGameSeconds: start, endGameSeconds: start + 2*60}
+}
+
+func TestEndPenaltyOnGoal(t *testing.T) {
+	tests := []struct {
+		name        string
+		penalties   []PenaltyRecord
+		goalSeconds []int
+		// End of each penalty, and whether a goal ended it, after the goals
+		wantEnds    []int
+		wantEndedBy []bool
+	}{
+		{
+			name:        "minor",
+			penalties:   []PenaltyRecord{minor(penalizedTeamId, 100)},
+			goalSeconds: []int{150},
+			wantEnds:    []int{150},
+			wantEndedBy: []bool{true},
+		},
+		{
+			name:        "bench minor",
+			penalties:   []PenaltyRecord{{teamId: penalizedTeamId, severity: "Bench Minor", minutes: 2, startGameSeconds: 100, endGameSeconds: 220}},
+			goalSeconds: []int{150},
+			wantEnds:    []int{150},
+			wantEndedBy: []bool{true},
+		},
+		{
+			name:        "double minor in its first half",
+			penalties:   []PenaltyRecord{{teamId: penalizedTeamId, severity: "Minor", minutes: 4, startGameSeconds: 100, endGameSeconds: 340}},
+			goalSeconds: []int{150},
+			wantEnds:    []int{270},
+			wantEndedBy: []bool{true},
+		},
+		{
+			name:        "double minor in its second half",
+			penalties:   []PenaltyRecord{{teamId: penalizedTeamId, severity: "Minor", minutes: 4, startGameSeconds: 100, endGameSeconds: 340}},
+			goalSeconds: []int{250},
+			wantEnds:    []int{250},
+			wantEndedBy: []bool{true},
+		},
+		{
+			name:        "double minor scored on twice",
+			penalties:   []PenaltyRecord{{teamId: penalizedTeamId, severity: "Minor", minutes: 4, startGameSeconds: 100, endGameSeconds: 340}},
+			goalSeconds: []int{150, 200},
+			wantEnds:    []int{200},
+			wantEndedBy: []bool{true},
+		},
+		{
+			name:        "major",
+			penalties:   []PenaltyRecord{{teamId: penalizedTeamId, severity: "Major", minutes: 5, startGameSeconds: 100, endGameSeconds: 400}},
+			goalSeconds: []int{150},
+			wantEnds:    []int{400},
+			wantEndedBy: []bool{false},
+		},
+		{
+			name:        "scoring team's own minor",
+			penalties:   []PenaltyRecord{minor(scoringTeamId, 100)},
+			goalSeconds: []int{150},
+			wantEnds:    []int{220},
+			wantEndedBy: []bool{false},
+		},
+		{
+			name:        "expired minor",
+			penalties:   []PenaltyRecord{minor(penalizedTeamId, 100)},
+			goalSeconds: []int{220},
+			wantEnds:    []int{220},
+			wantEndedBy: []bool{false},
+		},
+		{
+			name: "coincidental minors",
+			penalties: []PenaltyRecord{
+				minor(scoringTeamId, 100),
+				minor(penalizedTeamId, 100),
+			},
+			goalSeconds: []int{150},
+			wantEnds:    []int{220, 220},
+			wantEndedBy: []bool{false, false},
+		},
+		{
+			// The minor on top of the coincidental pair is the one released,
+			// even though the coincidental one would expire first
+			name: "power play on top of coincidental minors",
+			penalties: []PenaltyRecord{
+				minor(scoringTeamId, 100),
+				minor(penalizedTeamId, 100),
+				minor(penalizedTeamId, 110),
+			},
+			goalSeconds: []int{150},
+			wantEnds:    []int{220, 220, 150},
+			wantEndedBy: []bool{false, false, true},
+		},
+		{
+			name: "extra minor called with coincidental minors",
+			penalties: []PenaltyRecord{
+				minor(penalizedTeamId, 100),
+				minor(scoringTeamId, 100),
+				minor(penalizedTeamId, 100),
+			},
+			goalSeconds: []int{150},
+			wantEnds:    []int{220, 220, 150},
+			wantEndedBy: []bool{false, false, true},
+		},
+		{
+			name: "5v3 releases the first to expire",
+			penalties: []PenaltyRecord{
+				minor(penalizedTeamId, 130),
+				minor(penalizedTeamId, 100),
+			},
+			goalSeconds: []int{150},
+			wantEnds:    []int{250, 150},
+			wantEndedBy: []bool{false, true},
+		},
+		{
+			name: "5v3 scored on twice",
+			penalties: []PenaltyRecord{
+				minor(penalizedTeamId, 100),
+				minor(penalizedTeamId, 130),
+			},
+			goalSeconds: []int{150, 160},
+			wantEnds:    []int{150, 160},
+			wantEndedBy: []bool{true, true},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for _, goalSeconds := range test.goalSeconds {
+				endPenaltyOnGoal(test.penalties, scoringTeamId, goalSeconds)
+			}
+
+			for i, penalty := range test.penalties {
+				if penalty.endGameSeconds != test.wantEnds[i] || penalty.endedByGoal != test.wantEndedBy[i] {
+					t.Errorf("penalty %d ends at %d (endedByGoal %t), want %d (%t)",
+						i, penalty.endGameSeconds, penalty.endedByGoal, test.wantEnds[i], test.wantEndedBy[i])
+				}
+			}
+		})
+	}
+}
+
+func TestGetPenaltyRecordsReleasesOnlyOnPowerPlayGoals(t *testing.T) {
+	penaltyPlay := func(eventIdx int, periodTime string) Play {
+		return Play{Play: model.Play{
+			About:  model.About{EventIdx: eventIdx, Period: 1, PeriodTime: periodTime},
+			Result: model.Result{EventTypeId: "PENALTY", PenaltySeverity: "Minor", PenaltyMinutes: 2},
+			Team:   model.TeamRef{Id: penalizedTeamId},
+		}}
+	}
+	goalPlay := func(eventIdx int, periodTime string, teamId int, strength StrengthState) Play {
+		return Play{
+			Play: model.Play{
+				About:  model.About{EventIdx: eventIdx, Period: 1, PeriodTime: periodTime},
+				Result: model.Result{EventTypeId: "GOAL"},
+				Team:   model.TeamRef{Id: teamId},
+			},
+			Strength: strength,
+		}
+	}
+	// The away team is on the power play
+	powerPlay := StrengthState{AwaySkaters: 5, HomeSkaters: 4, AwayGoalie: true, HomeGoalie: true}
+
+	tests := []struct {
+		name    string
+		goal    Play
+		wantEnd int
+	}{
+		{"power-play goal", goalPlay(2, "01:30", scoringTeamId, powerPlay), 90},
+		{"short-handed goal", goalPlay(2, "01:30", penalizedTeamId, powerPlay), 180},
+		{"even-strength goal", goalPlay(2, "01:30", scoringTeamId, StrengthState{AwaySkaters: 4, HomeSkaters: 4, AwayGoalie: true, HomeGoalie: true}), 180},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			feed := GameFeed{
+				Game:  model.Game{AwayTeamId: scoringTeamId, HomeTeamId: penalizedTeamId},
+				Plays: []Play{penaltyPlay(1, "01:00"), test.goal},
+			}
+
+			penalties := GetPenaltyRecords(1, feed)
+			if len(penalties) != 1 {
+				t.Fatalf("GetPenaltyRecords returned %d penalties, want 1", len(penalties))
+			}
+			if penalties[0].endGameSeconds != test.wantEnd {
+				t.Errorf("penalty ends at %d, want %d", penalties[0].endGameSeconds, test.wantEnd)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"sort"
+
+	"github.com/gavswe19/ice-pipelines/model"
+)
+
+type strengthChange struct {
+	seconds  int
+	strength StrengthState
+}
+
+type toiSplit struct {
+	ev int
+	pp int
+	sh int
+}
+
+// Builds a stat line for every skater and goalie in the boxscore. Faceoffs
+// are counted from the plays and TOI is split by strength from the shifts.
+func GetPlayerGameStats(gamePk int, boxscore model.BoxscoreResponse, feed GameFeed, shifts []model.Shift) []PlayerGameStatsRecord {
+	awayTeamId := feed.Game.AwayTeamId
+	homeTeamId := feed.Game.HomeTeamId
+
+	faceoffWins := make(map[int]int)
+	faceoffLosses := make(map[int]int)
+	for _, play := range feed.Plays {
+		if play.Result.EventTypeId != "FACEOFF" {
+			continue
+		}
+		for _, player := range play.Players {
+			switch player.PlayerType {
+			case "Winner":
+				faceoffWins[player.Player.PlayerId]++
+			case "Loser":
+				faceoffLosses[player.Player.PlayerId]++
+			}
+		}
+	}
+
+	toiSplits := getToiSplits(feed, shifts)
+
+	var records []PlayerGameStatsRecord
+	teams := []struct {
+		teamId int
+		stats  model.TeamPlayerStats
+	}{
+		{awayTeamId, boxscore.PlayerByGameStats.AwayTeam},
+		{homeTeamId, boxscore.PlayerByGameStats.HomeTeam},
+	}
+
+	for _, team := range teams {
+		skaters := append(append([]model.SkaterStats{}, team.stats.Forwards...), team.stats.Defense...)
+		for _, skater := range skaters {
+			split := toiSplits[skater.PlayerId]
+			records = append(records, PlayerGameStatsRecord{
+				gamePk:        gamePk,
+				playerId:      skater.PlayerId,
+				teamId:        team.teamId,
+				position:      skater.Position,
+				goals:         skater.Goals,
+				assists:       skater.Assists,
+				shots:         skater.Sog,
+				hits:          skater.Hits,
+				blocks:        skater.BlockedShots,
+				pim:           skater.Pim,
+				toiSeconds:    model.PeriodTimeToSeconds(skater.Toi),
+				toiEvSeconds:  split.ev,
+				toiPpSeconds:  split.pp,
+				toiShSeconds:  split.sh,
+				faceoffWins:   faceoffWins[skater.PlayerId],
+				faceoffLosses: faceoffLosses[skater.PlayerId],
+			})
+		}
+
+		for _, goalie := range team.stats.Goalies {
+			split := toiSplits[goalie.PlayerId]
+			records = append(records, PlayerGameStatsRecord{
+				gamePk:       gamePk,
+				playerId:     goalie.PlayerId,
+				teamId:       team.teamId,
+				position:     "G",
+				pim:          goalie.Pim,
+				toiSeconds:   model.PeriodTimeToSeconds(goalie.Toi),
+				toiEvSeconds: split.ev,
+				toiPpSeconds: split.pp,
+				toiShSeconds: split.sh,
+				saves:        goalie.Saves,
+				shotsAgainst: goalie.ShotsAgainst,
+				decision:     goalie.Decision,
+			})
+		}
+	}
+
+	return records
+}
+
+// Splits every player's shift time into even strength, power play and
+// shorthanded seconds, using the strength of the most recent play
+func getToiSplits(feed GameFeed, shifts []model.Shift) map[int]toiSplit {
+	awayTeamId := feed.Game.AwayTeamId
+
+	periodChanges := make(map[int][]strengthChange)
+	for _, play := range feed.Plays {
+		period := play.About.Period
+		periodChanges[period] = append(periodChanges[period], strengthChange{
+			seconds:  model.PeriodTimeToSeconds(play.About.PeriodTime),
+			strength: play.Strength,
+		})
+	}
+	for _, changes := range periodChanges {
+		sort.SliceStable(changes, func(i, j int) bool { return changes[i].seconds < changes[j].seconds })
+	}
+
+	splits := make(map[int]toiSplit)
+	for _, shift := range shifts {
+		changes := periodChanges[shift.Period]
+		if len(changes) == 0 {
+			continue
+		}
+
+		split := splits[shift.PlayerId]
+		current := 0
+		for second := model.PeriodTimeToSeconds(shift.StartTime); second < model.PeriodTimeToSeconds(shift.EndTime); second++ {
+			for current+1 < len(changes) && changes[current+1].seconds <= second {
+				current++
+			}
+
+			switch changes[current].strength.advantage(shift.TeamId == awayTeamId) {
+			case 1:
+				split.pp++
+			case -1:
+				split.sh++
+			default:
+				split.ev++
+			}
+		}
+		splits[shift.PlayerId] = split
+	}
+
+	return splits
+}
+
+// Returns 1 when the team has more skaters than its opponent, -1 when it has
+// fewer and 0 when even. An extra attacker for a pulled goalie is ignored.
+func (s StrengthState) advantage(isAway bool) int {
+	own, opp := s.HomeSkaters, s.AwaySkaters
+	ownGoalie, oppGoalie := s.HomeGoalie, s.AwayGoalie
+	if isAway {
+		own, opp = opp, own
+		ownGoalie, oppGoalie = oppGoalie, ownGoalie
+	}
+
+	if !ownGoalie && own > 0 {
+		own--
+	}
+	if !oppGoalie && opp > 0 {
+		opp--
+	}
+
+	switch {
+	case own > opp:
+		return 1
+	case own < opp:
+		return -1
+	}
+	return 0
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/gavswe19/ice-pipelines/model"
+)
+
+// Returns the position code (C, L, R, D, G) of every player with a shift in
+// the game. The gamecenter roster is used where present, with player_bio and
+// then players filling in the rest. Players with no known position are left
+// as "".
+func GetPlayerPositions(db *sql.DB, feed GameFeed, shifts []model.Shift) (map[int]string, error) {
+	positions := make(map[int]string)
+	for playerId, position := range feed.Positions {
+		positions[playerId] = position
+	}
+
+	missingValueStrings := make([]string, 0)
+	missingValueArgs := make([]interface{}, 0)
+	for _, shift := range shifts {
+		if _, ok := positions[shift.PlayerId]; ok {
+			continue
+		}
+		positions[shift.PlayerId] = ""
+		missingValueStrings = append(missingValueStrings, "?")
+		missingValueArgs = append(missingValueArgs, shift.PlayerId)
+	}
+
+	if len(missingValueArgs) == 0 {
+		return positions, nil
+	}
+
+	query := fmt.Sprintf(`
+	SELECT p.player_id, COALESCE(pb.position, p.position, '')
+	FROM players p
+	LEFT JOIN player_bio pb ON pb.player_id = p.player_id
+	WHERE p.player_id IN (%s)
+	UNION
+	SELECT pb.player_id, COALESCE(pb.position, '')
+	FROM player_bio pb
+	WHERE pb.player_id IN (%s)`, strings.Join(missingValueStrings, ","), strings.Join(missingValueStrings, ","))
+
+	results, err := db.Query(query, append(missingValueArgs, missingValueArgs...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer results.Close()
+
+	for results.Next() {
+		var playerId int
+		var position string
+		err = results.Scan(&playerId, &position)
+		if err != nil {
+			return nil, err
+		}
+		// A player can come back from both tables; an empty position is
+		// unknown and must not replace one that is known
+		if position != "" {
+			positions[playerId] = position
+		}
+	}
+
+	return positions, results.Err()
+}
+
+// Splits skaters into forwards and defensemen. Skaters with no known
+// position are treated as forwards.
+func splitSkaters(skaterIds []int, positions map[int]string) (forwardIds []int, defenseIds []int) {
+	for _, playerId := range skaterIds {
+		if positions[playerId] == "D" {
+			defenseIds = append(defenseIds, playerId)
+		} else {
+			forwardIds = append(forwardIds, playerId)
+		}
+	}
+	return
+}
+
+// Id columns in team_season_forward_lines and team_season_defense_pairs
+const (
+	forwardLineSize = 5
+	defensePairSize = 3
+)
+
+// Whether a unit fits a registry row. A unit that doesn't, e.g. six forwards
+// with the goalie pulled, is logged and left without a hash rather than
+// being cut down to players who were not its whole unit.
+func fitsRegistry(gamePk int, eventIdx int, teamId int, unit string, ids []int, size int) bool {
+	if len(ids) <= size {
+		return true
+	}
+	fmt.Println(fmt.Sprintf("Skipping %d-player %s for teamId %d at eventIdx %d of gamePk %d", len(ids), unit, teamId, eventIdx, gamePk))
+	return false
+}
+
+// Pads ids to a fixed number of registry columns
+func padIds(ids []int, size int) []interface{} {
+	padded := make([]interface{}, size)
+	for i := range padded {
+		padded[i] = 0
+		if i < len(ids) {
+			padded[i] = ids[i]
+		}
+	}
+	return padded
+}
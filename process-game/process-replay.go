@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/gavswe19/ice-pipelines/database"
+	"github.com/gavswe19/ice-pipelines/message"
+	"github.com/gavswe19/ice-pipelines/nhlapi"
+)
+
+// Rebuilds every game with an archived play feed from the archive alone.
+// Runs locally with ARCHIVE_MODE=replay rather than as a Lambda.
+func ReplayGames(ctx context.Context) error {
+	db, err := database.GetDatabase()
+	if err != nil {
+		return err
+	}
+
+	gamePkList := []int{}
+	for _, endpoint := range []string{nhlapi.EndpointLegacyGameFeed, nhlapi.EndpointPlayByPlay} {
+		ids, err := nhlClient.Archive().Ids(ctx, endpoint, "gamePk")
+		if err != nil {
+			return err
+		}
+		gamePkList = append(gamePkList, ids...)
+	}
+	fmt.Printf("Replaying %d archived games\n", len(gamePkList))
+
+	var errs []error
+	for _, gamePk := range gamePkList {
+		envelope := message.New(message.EntityGame, gamePk, "replay")
+		envelope.Force = true
+
+		err := processGameEnvelope(ctx, db, envelope)
+		if err != nil {
+			fmt.Println(fmt.Sprintf("Error replaying GamePk %s: %s", strconv.Itoa(gamePk), err))
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
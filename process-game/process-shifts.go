@@ -0,0 +1,39 @@
+package main
+
+import "github.com/gavswe19/ice-pipelines/model"
+
+type shiftGoal struct {
+	period  int
+	seconds int
+}
+
+// Flattens the shift chart into shift records, flagging shifts that ended on
+// a goal by either team
+func GetShiftRecords(gamePk int, shifts []model.Shift, plays []Play) []ShiftRecord {
+	goals := make(map[shiftGoal]bool)
+	for _, play := range plays {
+		if play.Result.EventTypeId == "GOAL" {
+			goals[shiftGoal{play.About.Period, model.PeriodTimeToSeconds(play.About.PeriodTime)}] = true
+		}
+	}
+
+	shiftRecordList := make([]ShiftRecord, 0, len(shifts))
+	for _, shift := range shifts {
+		start := model.PeriodTimeToSeconds(shift.StartTime)
+		end := model.PeriodTimeToSeconds(shift.EndTime)
+
+		shiftRecordList = append(shiftRecordList, ShiftRecord{
+			gamePk:        gamePk,
+			playerId:      shift.PlayerId,
+			teamId:        shift.TeamId,
+			period:        shift.Period,
+			shiftNumber:   shift.ShiftNumber,
+			startSeconds:  start,
+			endSeconds:    end,
+			duration:      end - start,
+			endedWithGoal: goals[shiftGoal{shift.Period, end}],
+		})
+	}
+
+	return shiftRecordList
+}
@@ -0,0 +1,49 @@
+package main
+
+var shootoutResults = map[string]string{
+	"GOAL":                "GOAL",
+	"SHOT":                "SAVE",
+	"MISSED_SHOT":         "MISS",
+	"FAILED_SHOT_ATTEMPT": "FAILED",
+}
+
+// Removes every shootout play from the feed so play_by_play and the on-ice
+// and line processing only see regulation and overtime, and returns the
+// shootout's attempts. Teams alternate shooters, so each pair is a round.
+func SplitShootout(gamePk int, feed GameFeed) (GameFeed, []ShootoutAttemptRecord) {
+	plays := make([]Play, 0, len(feed.Plays))
+	var shootoutAttemptList []ShootoutAttemptRecord
+
+	for _, play := range feed.Plays {
+		if play.About.PeriodType != "SHOOTOUT" {
+			plays = append(plays, play)
+			continue
+		}
+
+		result, ok := shootoutResults[play.Result.EventTypeId]
+		if !ok {
+			continue
+		}
+
+		attempt := ShootoutAttemptRecord{
+			gamePk:   gamePk,
+			eventIdx: play.About.EventIdx,
+			round:    len(shootoutAttemptList)/2 + 1,
+			teamId:   play.Team.Id,
+			result:   result,
+			shotType: play.Result.SecondaryType,
+		}
+		for _, player := range play.Players {
+			switch player.PlayerType {
+			case "Shooter", "Scorer":
+				attempt.shooterId = player.Player.PlayerId
+			case "Goalie":
+				attempt.goalieId = player.Player.PlayerId
+			}
+		}
+		shootoutAttemptList = append(shootoutAttemptList, attempt)
+	}
+
+	feed.Plays = plays
+	return feed, shootoutAttemptList
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+)
+
+type StrengthState struct {
+	AwaySkaters int
+	HomeSkaters int
+	AwayGoalie  bool
+	HomeGoalie  bool
+}
+
+// Sets the strength state of every play, preferring the feed's situationCode
+// and falling back to counting the on-ice records for the play
+func SetStrengthStates(feed GameFeed, onIceRecords []OnIceRecord) {
+	awayTeamId := feed.Game.AwayTeamId
+
+	awayOnIce := make(map[int]OnIceRecord)
+	homeOnIce := make(map[int]OnIceRecord)
+	for _, oir := range onIceRecords {
+		if oir.teamId == awayTeamId {
+			awayOnIce[oir.eventIdx] = oir
+		} else {
+			homeOnIce[oir.eventIdx] = oir
+		}
+	}
+
+	for i, play := range feed.Plays {
+		strength, ok := parseSituationCode(play.SituationCode)
+		if !ok {
+			strength = onIceStrengthState(awayOnIce[play.About.EventIdx], homeOnIce[play.About.EventIdx])
+		}
+		feed.Plays[i].Strength = strength
+	}
+}
+
+// situationCode is four digits: away goalie, away skaters, home skaters,
+// home goalie. A pulled goalie is counted as an extra skater.
+func parseSituationCode(code string) (StrengthState, bool) {
+	if len(code) != 4 {
+		return StrengthState{}, false
+	}
+
+	digits := make([]int, 4)
+	for i, c := range code {
+		digit, err := strconv.Atoi(string(c))
+		if err != nil {
+			return StrengthState{}, false
+		}
+		digits[i] = digit
+	}
+
+	return StrengthState{
+		AwayGoalie:  digits[0] == 1,
+		AwaySkaters: digits[1],
+		HomeSkaters: digits[2],
+		HomeGoalie:  digits[3] == 1,
+	}, true
+}
+
+func onIceStrengthState(away OnIceRecord, home OnIceRecord) StrengthState {
+	return StrengthState{
+		AwaySkaters: away.skaterCount(),
+		HomeSkaters: home.skaterCount(),
+		AwayGoalie:  away.goalieId != 0,
+		HomeGoalie:  home.goalieId != 0,
+	}
+}
+
+func (oir OnIceRecord) skaterCount() int {
+	count := 0
+	for _, skaterId := range []int{oir.skaterId1, oir.skaterId2, oir.skaterId3, oir.skaterId4, oir.skaterId5, oir.skaterId6} {
+		if skaterId != 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// Skater counts as "5v4" from the perspective of the given team. Plays
+// without a team are labelled from the home team's perspective.
+func (s StrengthState) Label(teamId int, awayTeamId int) string {
+	if s.AwaySkaters == 0 && s.HomeSkaters == 0 {
+		return ""
+	}
+
+	if teamId != 0 && teamId == awayTeamId {
+		return fmt.Sprintf("%dv%d", s.AwaySkaters, s.HomeSkaters)
+	}
+	return fmt.Sprintf("%dv%d", s.HomeSkaters, s.AwaySkaters)
+}
+
+// Whether the net the given team attacks is empty, i.e. its opponent has
+// pulled its goalie. A team's own pulled goalie does not count. Plays
+// without a team are never empty net.
+func (s StrengthState) EmptyNet(teamId int, awayTeamId int) bool {
+	if teamId == 0 || (s.AwaySkaters == 0 && s.HomeSkaters == 0) {
+		return false
+	}
+
+	if teamId == awayTeamId {
+		return !s.HomeGoalie
+	}
+	return !s.AwayGoalie
+}
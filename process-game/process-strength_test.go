@@ -0,0 +1,107 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/gavswe19/ice-pipelines/model"
+)
+
+const (
+	testAwayTeamId = 1
+	testHomeTeamId = 2
+)
+
+func TestParseSituationCode(t *testing.T) {
+	tests := []struct {
+		code      string
+		want      StrengthState
+		awayLabel string
+		homeLabel string
+		// Empty net from the shooting team's perspective
+		awayEmptyNet bool
+		homeEmptyNet bool
+	}{
+		{"1551", StrengthState{AwaySkaters: 5, HomeSkaters: 5, AwayGoalie: true, HomeGoalie: true}, "5v5", "5v5", false, false},
+		// Away goalie pulled for an extra attacker
+		{"0651", StrengthState{AwaySkaters: 6, HomeSkaters: 5, HomeGoalie: true}, "6v5", "5v6", false, true},
+		// Home goalie pulled, e.g. on a delayed penalty against the away team
+		{"1560", StrengthState{AwaySkaters: 5, HomeSkaters: 6, AwayGoalie: true}, "5v6", "6v5", true, false},
+		{"1441", StrengthState{AwaySkaters: 4, HomeSkaters: 4, AwayGoalie: true, HomeGoalie: true}, "4v4", "4v4", false, false},
+		{"1331", StrengthState{AwaySkaters: 3, HomeSkaters: 3, AwayGoalie: true, HomeGoalie: true}, "3v3", "3v3", false, false},
+		// Delayed penalty called during a power play, away and home
+		{"0641", StrengthState{AwaySkaters: 6, HomeSkaters: 4, HomeGoalie: true}, "6v4", "4v6", false, true},
+		{"1460", StrengthState{AwaySkaters: 4, HomeSkaters: 6, AwayGoalie: true}, "4v6", "6v4", true, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.code, func(t *testing.T) {
+			got, ok := parseSituationCode(test.code)
+			if !ok {
+				t.Fatalf("parseSituationCode(%q) failed", test.code)
+			}
+			if got != test.want {
+				t.Errorf("parseSituationCode(%q) = %+v, want %+v", test.code, got, test.want)
+			}
+
+			if label := got.Label(testAwayTeamId, testAwayTeamId); label != test.awayLabel {
+				t.Errorf("away Label = %q, want %q", label, test.awayLabel)
+			}
+			if label := got.Label(testHomeTeamId, testAwayTeamId); label != test.homeLabel {
+				t.Errorf("home Label = %q, want %q", label, test.homeLabel)
+			}
+			if emptyNet := got.EmptyNet(testAwayTeamId, testAwayTeamId); emptyNet != test.awayEmptyNet {
+				t.Errorf("away EmptyNet = %t, want %t", emptyNet, test.awayEmptyNet)
+			}
+			if emptyNet := got.EmptyNet(testHomeTeamId, testAwayTeamId); emptyNet != test.homeEmptyNet {
+				t.Errorf("home EmptyNet = %t, want %t", emptyNet, test.homeEmptyNet)
+			}
+		})
+	}
+}
+
+func TestParseSituationCodeRejectsMalformedCodes(t *testing.T) {
+	for _, code := range []string{"", "155", "15511", "1a51"} {
+		if _, ok := parseSituationCode(code); ok {
+			t.Errorf("parseSituationCode(%q) succeeded, want failure", code)
+		}
+	}
+}
+
+func TestStrengthStateWithoutTeam(t *testing.T) {
+	strength := StrengthState{AwaySkaters: 6, HomeSkaters: 5, HomeGoalie: true}
+
+	if label := strength.Label(0, testAwayTeamId); label != "5v6" {
+		t.Errorf("Label = %q, want the home team's %q", label, "5v6")
+	}
+	if strength.EmptyNet(0, testAwayTeamId) {
+		t.Error("EmptyNet for a play without a team = true, want false")
+	}
+	if label := (StrengthState{}).Label(testAwayTeamId, testAwayTeamId); label != "" {
+		t.Errorf("Label of an unknown strength = %q, want empty", label)
+	}
+}
+
+func TestSetStrengthStatesFallsBackToOnIceCounts(t *testing.T) {
+	feed := GameFeed{
+		Game: model.Game{AwayTeamId: testAwayTeamId, HomeTeamId: testHomeTeamId},
+		Plays: []Play{
+			{Play: model.Play{About: model.About{EventIdx: 1}, SituationCode: "1451"}},
+			{Play: model.Play{About: model.About{EventIdx: 2}}},
+		},
+	}
+	onIce := []OnIceRecord{
+		{teamId: testAwayTeamId, eventIdx: 2, skaterId1: 11, skaterId2: 12, skaterId3: 13, skaterId4: 14, skaterId5: 15, skaterId6: 16},
+		{teamId: testHomeTeamId, eventIdx: 2, skaterId1: 21, skaterId2: 22, skaterId3: 23, skaterId4: 24, skaterId5: 25, goalieId: 30},
+	}
+
+	SetStrengthStates(feed, onIce)
+
+	want := StrengthState{AwaySkaters: 4, HomeSkaters: 5, AwayGoalie: true, HomeGoalie: true}
+	if got := feed.Plays[0].Strength; got != want {
+		t.Errorf("situationCode play Strength = %+v, want %+v", got, want)
+	}
+	want = StrengthState{AwaySkaters: 6, HomeSkaters: 5, HomeGoalie: true}
+	if got := feed.Plays[1].Strength; got != want {
+		t.Errorf("on-ice fallback Strength = %+v, want %+v", got, want)
+	}
+}
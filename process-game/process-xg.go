@@ -0,0 +1,31 @@
+package main
+
+import (
+	"github.com/gavswe19/ice-pipelines/model"
+	"github.com/gavswe19/ice-pipelines/xg"
+)
+
+// Builds xG shots for the game's unblocked shot attempts
+func GetShots(feed GameFeed) []xg.Shot {
+	awayTeamId := feed.Game.AwayTeamId
+	events := make([]xg.Event, 0, len(feed.Plays))
+
+	for _, play := range feed.Plays {
+		events = append(events, xg.Event{
+			GamePk:        feed.Game.GamePk,
+			EventIdx:      play.About.EventIdx,
+			Period:        play.About.Period,
+			PeriodType:    play.About.PeriodType,
+			PeriodSeconds: model.PeriodTimeToSeconds(play.About.PeriodTime),
+			EventTypeId:   play.Result.EventTypeId,
+			SecondaryType: play.Result.SecondaryType,
+			TeamId:        play.Team.Id,
+			X:             float64(play.NormalizedCoordinates.X),
+			Y:             float64(play.NormalizedCoordinates.Y),
+			StrengthState: play.Strength.Label(play.Team.Id, awayTeamId),
+			EmptyNet:      play.Strength.EmptyNet(play.Team.Id, awayTeamId),
+		})
+	}
+
+	return xg.BuildShots(events)
+}
@@ -3,48 +3,39 @@ package main
 import (
 	"context"
 	"database/sql"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io/ioutil"
 	"log"
-	"net/http"
 	"strconv"
 	"strings"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
 	"github.com/gavswe19/ice-pipelines/database"
+	"github.com/gavswe19/ice-pipelines/deadletter"
+	"github.com/gavswe19/ice-pipelines/failure"
+	"github.com/gavswe19/ice-pipelines/message"
+	"github.com/gavswe19/ice-pipelines/model"
+	"github.com/gavswe19/ice-pipelines/nhlapi"
 )
 
-type SeasonTotals struct {
-	Season             int      `json:"season"`
-	GameTypeId         int      `json:"gameTypeId"`
-	LeagueAbbrev       string   `json:"leagueAbbrev"`
-	TeamName           string   `json:"teamName"`
-	Sequence           int      `json:"sequence"`
-	GamesPlayed        *int     `json:"gamesPlayed"`
-	Goals              *int     `json:"goals"`
-	Assists            *int     `json:"assists"`
-	Points             *int     `json:"points"`
-	PlusMinus          *int     `json:"plusMinus"`
-	PowerPlayGoals     *int     `json:"powerPlayGoals"`
-	PowerPlayPoints    *int     `json:"powerPlayPoints"`
-	ShorthandedPoints  *int     `json:"shorthandedPoints"`
-	GameWinningGoals   *int     `json:"gameWinningGoals"`
-	OtGoals            *int     `json:"otGoals"`
-	Shots              *int64   `json:"shots"`
-	ShootingPctg       *float64 `json:"shootingPctg"`
-	FaceoffWinningPctg *float64 `json:"faceoffWinningPctg"`
-	AvgToi             *string  `json:"avgToi"`
-	ShorthandedGoals   *int     `json:"shorthandedGoals"`
-	Pim                *int     `json:"pim"`
-}
-
-type PlayerData struct {
-	SeasonTotals []SeasonTotals `json:"seasonTotals"`
-}
+// Set from the environment in main
+var nhlClient *nhlapi.Client
 
 func main() {
+	var err error
+	nhlClient, err = nhlapi.FromEnv(context.TODO())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if nhlClient.Replaying() {
+		if err := replayPlayerSeasonTotals(context.TODO()); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	lambda.Start(Handler)
 }
 
@@ -62,62 +53,95 @@ func main() {
 // 	Handler(ctx, sqsEvent)
 // }
 
-func Handler(ctx context.Context, sqsEvent events.SQSEvent) {
-	eventRecord := sqsEvent.Records[0]
-	body := eventRecord.Body
-	playerId, err := strconv.Atoi(body)
+func Handler(ctx context.Context, sqsEvent events.SQSEvent) (events.SQSEventResponse, error) {
+	db, err := database.GetDatabase()
+	if err != nil {
+		return events.SQSEventResponse{}, err
+	}
+	response := events.SQSEventResponse{}
 
-	db := database.GetDatabase()
-	tx, err := db.Begin()
-	println("Start Transaction")
+	for _, eventRecord := range sqsEvent.Records {
+		err := processPlayerSeasonTotalsMessage(ctx, db, eventRecord.Body)
+		if err != nil && deadletter.ShouldRetry(ctx, eventRecord, err) {
+			response.BatchItemFailures = append(response.BatchItemFailures, events.SQSBatchItemFailure{ItemIdentifier: eventRecord.MessageId})
+		}
+	}
 
-	processPlayerSeasonTotals(tx, playerId)
+	return response, nil
+}
 
-	err = tx.Commit()
+func processPlayerSeasonTotalsMessage(ctx context.Context, db *sql.DB, body string) error {
+	envelope, err := message.Parse(body, message.EntityPlayerSeasonTotals)
 	if err != nil {
-		log.Fatal(err)
+		return failure.Permanent(err)
 	}
-	println("Committed Transaction")
+	return loadPlayerSeasonTotals(ctx, db, envelope.Id)
 }
 
-func processPlayerSeasonTotals(tx *sql.Tx, playerId int) {
-	teamNameToID := teamNameIdMap()
+// Rebuilds player_season_totals for every player with an archived landing
+// payload
+func replayPlayerSeasonTotals(ctx context.Context) error {
+	db, err := database.GetDatabase()
+	if err != nil {
+		return err
+	}
 
-	// API endpoint URL
-	apiUrl := fmt.Sprintf("https://api-web.nhle.com/v1/player/%d/landing", playerId)
+	playerIdList, err := nhlClient.Archive().Ids(ctx, nhlapi.EndpointPlayerLanding, "playerId")
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Replaying %d archived players\n", len(playerIdList))
+
+	var errs []error
+	for _, playerId := range playerIdList {
+		if err := loadPlayerSeasonTotals(ctx, db, playerId); err != nil {
+			fmt.Println("Error replaying player:", playerId, err)
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
 
-	// Make an HTTP GET request to the API
-	response, err := http.Get(apiUrl)
+func loadPlayerSeasonTotals(ctx context.Context, db *sql.DB, playerId int) error {
+	tx, err := db.Begin()
 	if err != nil {
-		fmt.Println("Error:", err)
-		return
+		return err
 	}
-	defer response.Body.Close()
+	println("Start Transaction")
 
-	// Read the response body
-	responseBody, err := ioutil.ReadAll(response.Body)
+	err = processPlayerSeasonTotals(ctx, tx, playerId)
 	if err != nil {
-		fmt.Println("Error:", err)
-		return
+		tx.Rollback()
+		return err
 	}
 
-	// Create a struct to hold the parsed JSON data
-	var playerData PlayerData
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+	println("Committed Transaction")
+	return nil
+}
 
-	// Parse the JSON response into the struct
-	err = json.Unmarshal(responseBody, &playerData)
+func processPlayerSeasonTotals(ctx context.Context, tx *sql.Tx, playerId int) error {
+	teamNameToID, err := teamNameIdMap(ctx)
 	if err != nil {
-		fmt.Println("Error parsing JSON:", err)
-		return
+		return err
 	}
 
-	// Access the list of seasonTotals
-	seasonTotals := playerData.SeasonTotals
+	landing, err := nhlClient.PlayerLanding(ctx, playerId)
+	if err != nil {
+		return err
+	}
 
-	insertPlayerSeasonTotals(tx, playerId, seasonTotals, teamNameToID)
+	return insertPlayerSeasonTotals(tx, playerId, landing.SeasonTotals, teamNameToID)
 }
 
-func insertPlayerSeasonTotals(tx *sql.Tx, playerId int, seasonTotals []SeasonTotals, teamNameToID map[string]int) {
+func insertPlayerSeasonTotals(tx *sql.Tx, playerId int, seasonTotals []model.SeasonTotals, teamNameToID map[string]int) error {
+	if len(seasonTotals) == 0 {
+		return nil
+	}
+
 	playerSeasonTotalsStrings := make([]string, 0, len(seasonTotals))
 	playerSeasonTotalsValueArgs := make([]interface{}, 0, len(seasonTotals)*9)
 
@@ -155,11 +179,11 @@ func insertPlayerSeasonTotals(tx *sql.Tx, playerId int, seasonTotals []SeasonTot
 	result, err := tx.Exec(stmt, playerSeasonTotalsValueArgs...)
 
 	if err != nil {
-		tx.Rollback()
 		fmt.Println("Rolling back")
-		log.Fatal(err)
+		return err
 	}
 
 	rows_affected, err := result.RowsAffected()
 	println(fmt.Sprintf("Inserted %s records into player_season_totals", strconv.Itoa(int(rows_affected))))
+	return nil
 }
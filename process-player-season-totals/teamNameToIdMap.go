@@ -1,46 +1,35 @@
 package main
 
 import (
-	"encoding/json"
-	"log"
-	"net/http"
+	"context"
+	"errors"
+	"time"
 )
 
-func teamNameIdMap() map[string]int {
-	// API endpoint URL
-	apiUrl := "https://statsapi.web.nhl.com/api/v1/teams?season=20212022"
-
-	// Make an HTTP GET request to the API
-	response, err := http.Get(apiUrl)
+// Maps each team name, as season totals name them, to its id. Full names
+// cover every team the league has had; common names cover current teams
+// while the season is on.
+func teamNameIdMap(ctx context.Context) (map[string]int, error) {
+	statsTeams, err := nhlClient.StatsTeams(ctx)
 	if err != nil {
-		log.Fatalln("Error:", err)
-	}
-	defer response.Body.Close()
-
-	// Parse the JSON response
-	var data map[string]interface{}
-	decoder := json.NewDecoder(response.Body)
-	if err := decoder.Decode(&data); err != nil {
-		log.Fatalln("Error decoding JSON:", err)
+		return nil, err
 	}
-
-	// Check if the "teams" key exists in the JSON data
-	teams, ok := data["teams"].([]interface{})
-	if !ok {
-		log.Fatalln("Error: 'teams' key not found in JSON data")
+	if len(statsTeams.Data) == 0 {
+		return nil, errors.New("Error: no teams in stats teams response")
 	}
 
-	// Create a map to store teamName to teamId mappings
 	teamNameToID := make(map[string]int)
+	for _, team := range statsTeams.ToTeams() {
+		teamNameToID[team.Name] = team.Id
+	}
 
-	// Iterate through the teams and populate the map
+	teams, err := nhlClient.Teams(ctx, time.Now())
+	if err != nil {
+		return nil, err
+	}
 	for _, team := range teams {
-		if teamData, ok := team.(map[string]interface{}); ok {
-			teamName := teamData["teamName"].(string)
-			teamID := int(teamData["id"].(float64))
-			teamNameToID[teamName] = teamID
-		}
+		teamNameToID[team.CommonName] = team.Id
 	}
 
-	return teamNameToID
+	return teamNameToID, nil
 }
@@ -2,106 +2,103 @@ package main
 
 import (
 	"context"
-	"encoding/json"
+	"database/sql"
+	"errors"
 	"fmt"
-	"io"
 	"log"
-	"net/http"
-	"strconv"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
 	"github.com/gavswe19/ice-pipelines/database"
+	"github.com/gavswe19/ice-pipelines/deadletter"
+	"github.com/gavswe19/ice-pipelines/failure"
+	"github.com/gavswe19/ice-pipelines/message"
+	"github.com/gavswe19/ice-pipelines/model"
+	"github.com/gavswe19/ice-pipelines/nhlapi"
 )
 
-// Define the Go struct to match the JSON structure
-type Player struct {
-	PlayerId            int         `json:"playerId"`
-	IsActive            bool        `json:"isActive"`
-	CurrentTeamId       int         `json:"currentTeamId"`
-	CurrentTeamAbbrev   string      `json:"currentTeamAbbrev"`
-	FullTeamName        Translation `json:"fullTeamName"`
-	FirstName           Translation `json:"firstName"`
-	LastName            Translation `json:"lastName"`
-	TeamLogo            string      `json:"teamLogo"`
-	SweaterNumber       int         `json:"sweaterNumber"`
-	Position            string      `json:"position"`
-	Headshot            string      `json:"headshot"`
-	HeroImage           string      `json:"heroImage"`
-	HeightInInches      int         `json:"heightInInches"`
-	HeightInCentimeters int         `json:"heightInCentimeters"`
-	WeightInPounds      int         `json:"weightInPounds"`
-	WeightInKilograms   int         `json:"weightInKilograms"`
-	BirthDate           string      `json:"birthDate"`
-	BirthCity           Translation `json:"birthCity"`
-	BirthStateProvince  Translation `json:"birthStateProvince"`
-	BirthCountry        string      `json:"birthCountry"`
-	ShootsCatches       string      `json:"shootsCatches"`
-	DraftDetails        Draft       `json:"draftDetails"`
-}
+// Set from the environment in main
+var nhlClient *nhlapi.Client
 
-// Define a struct for fields that have translations
-type Translation struct {
-	Default string `json:"default"`
-	Fr      string `json:"fr,omitempty"` // Use 'omitempty' for optional fields
-}
+func main() {
+	var err error
+	nhlClient, err = nhlapi.FromEnv(context.TODO())
+	if err != nil {
+		log.Fatal(err)
+	}
 
-// Define the Draft struct for the draftDetails field
-type Draft struct {
-	Year        int    `json:"year"`
-	TeamAbbrev  string `json:"teamAbbrev"`
-	Round       int    `json:"round"`
-	PickInRound int    `json:"pickInRound"`
-	OverallPick int    `json:"overallPick"`
-}
+	if nhlClient.Replaying() {
+		if err := replayPlayers(context.TODO()); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
 
-func main() {
 	lambda.Start(Handler)
 }
 
-func Handler(ctx context.Context, sqsEvent events.SQSEvent) {
-	eventRecord := sqsEvent.Records[0]
-	eventBody := eventRecord.Body
-	playerId, err := strconv.Atoi(eventBody)
-
+func Handler(ctx context.Context, sqsEvent events.SQSEvent) (events.SQSEventResponse, error) {
+	db, err := database.GetDatabase()
 	if err != nil {
-		fmt.Println("Error handling SQS message:", err)
-		return
+		return events.SQSEventResponse{}, err
 	}
+	response := events.SQSEventResponse{}
 
-	// Replace this with the actual API URL you want to use
-	apiURL := fmt.Sprintf("https://api-web.nhle.com/v1/player/%d/landing", playerId)
+	for _, eventRecord := range sqsEvent.Records {
+		err := processPlayerMessage(ctx, db, eventRecord.Body)
+		if err != nil && deadletter.ShouldRetry(ctx, eventRecord, err) {
+			response.BatchItemFailures = append(response.BatchItemFailures, events.SQSBatchItemFailure{ItemIdentifier: eventRecord.MessageId})
+		}
+	}
 
-	// Make the HTTP GET request
-	resp, err := http.Get(apiURL)
+	return response, nil
+}
+
+func processPlayerMessage(ctx context.Context, db *sql.DB, eventBody string) error {
+	envelope, err := message.Parse(eventBody, message.EntityPlayer)
 	if err != nil {
-		fmt.Println("Error making GET request:", err)
-		return
+		return failure.Permanent(err)
 	}
-	defer resp.Body.Close()
+	return processPlayer(ctx, db, envelope.Id)
+}
 
-	// Read the response body
-	body, err := io.ReadAll(resp.Body)
+// Rebuilds player_bio for every player with an archived landing payload
+func replayPlayers(ctx context.Context) error {
+	db, err := database.GetDatabase()
 	if err != nil {
-		fmt.Println("Error reading response body:", err)
-		return
+		return err
 	}
 
-	// Unmarshal the JSON data into the Go struct
-	var player Player
-	err = json.Unmarshal(body, &player)
+	playerIdList, err := nhlClient.Archive().Ids(ctx, nhlapi.EndpointPlayerLanding, "playerId")
 	if err != nil {
-		fmt.Println("Error unmarshaling JSON:", err)
-		return
+		return err
+	}
+	fmt.Printf("Replaying %d archived players\n", len(playerIdList))
+
+	var errs []error
+	for _, playerId := range playerIdList {
+		if err := processPlayer(ctx, db, playerId); err != nil {
+			fmt.Println("Error replaying player:", playerId, err)
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func processPlayer(ctx context.Context, db *sql.DB, playerId int) error {
+	landing, err := nhlClient.PlayerLanding(ctx, playerId)
+	if err != nil {
+		return err
 	}
+	player := landing.ToPlayer()
 
 	// Print the struct to verify the data
 	fmt.Printf("%+v\n", player)
 
-	insertPlayer(player)
+	return insertPlayer(db, player)
 }
 
-func insertPlayer(player Player) {
+func insertPlayer(db *sql.DB, player model.Player) error {
 	query := `
 	INSERT INTO player_bio (
 		player_id, is_active, current_team_id, current_team_abbrev, full_team_name, first_name, last_name, full_name, sweater_number,
@@ -137,17 +134,16 @@ func insertPlayer(player Player) {
 		draft_overall_pick = VALUES(draft_overall_pick)
 	 `
 
-	db := database.GetDatabase()
 	_, err := db.Exec(
 		query,
-		player.PlayerId,
+		player.Id,
 		player.IsActive,
 		player.CurrentTeamId,
 		player.CurrentTeamAbbrev,
-		player.FullTeamName.Default, // Assuming using the default language for FullTeamName
-		player.FirstName.Default,    // Assuming using the default language for FirstName
-		player.LastName.Default,     // Assuming using the default language for LastName
-		fmt.Sprintf("%s %s", player.FirstName.Default, player.LastName.Default),
+		player.CurrentTeamName,
+		player.FirstName,
+		player.LastName,
+		player.FullName(),
 		player.SweaterNumber,
 		player.Position,
 		player.Headshot,
@@ -157,20 +153,20 @@ func insertPlayer(player Player) {
 		player.WeightInPounds,
 		player.WeightInKilograms,
 		player.BirthDate,
-		player.BirthCity.Default,          // Assuming using the default language for BirthCity
-		player.BirthStateProvince.Default, // Assuming using the default language for BirthStateProvince
+		player.BirthCity,
+		player.BirthStateProvince,
 		player.BirthCountry,
 		player.ShootsCatches,
-		player.DraftDetails.Year,
-		player.DraftDetails.TeamAbbrev,
-		player.DraftDetails.Round,
-		player.DraftDetails.PickInRound,
-		player.DraftDetails.OverallPick,
+		player.Draft.Year,
+		player.Draft.TeamAbbrev,
+		player.Draft.Round,
+		player.Draft.PickInRound,
+		player.Draft.OverallPick,
 	)
 
 	if err != nil {
-		log.Fatalf("Failed to insert player: %v", err)
-	} else {
-		fmt.Println("Player inserted successfully!")
+		return fmt.Errorf("Failed to insert player: %w", err)
 	}
+	fmt.Println("Player inserted successfully!")
+	return nil
 }
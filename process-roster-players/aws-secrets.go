@@ -1,53 +0,0 @@
-package main
-
-// Use this code snippet in your app.
-// If you need more information about configurations or implementing the sample code, visit the AWS docs:
-// https://aws.github.io/aws-sdk-go-v2/docs/getting-started/
-
-import (
-	"context"
-	"encoding/json"
-	"log"
-
-	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
-	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
-)
-
-type Secrets struct {
-	Username string `json:"username"`
-	Password string `json:"password"`
-	Host string `json:"host"`
-}
-
-func getAwsSecrets() (secrets Secrets){
-	secretName := "farm/mysql"
-	region := "us-east-1"
-
-	config, err := config.LoadDefaultConfig(context.TODO(), config.WithRegion(region))
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	// Create Secrets Manager client
-	svc := secretsmanager.NewFromConfig(config)
-
-	input := &secretsmanager.GetSecretValueInput{
-		SecretId:     aws.String(secretName),
-		VersionStage: aws.String("AWSCURRENT"), // VersionStage defaults to AWSCURRENT if unspecified
-	}
-
-	result, err := svc.GetSecretValue(context.TODO(), input)
-	if err != nil {
-		// For a list of exceptions thrown, see
-		// https://docs.aws.amazon.com/secretsmanager/latest/apireference/API_GetSecretValue.html
-		log.Fatal(err.Error())
-	}
-
-	// Decrypts secret using the associated KMS key.
-	secretString := *result.SecretString
-	
-	json.Unmarshal([]byte(secretString), &secrets);
-
-	return;
-}
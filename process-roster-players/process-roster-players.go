@@ -1,72 +1,71 @@
 package main
 
 import (
+	"context"
 	"database/sql"
-	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"log"
-	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
-	"github.com/go-sql-driver/mysql"
+	"github.com/gavswe19/ice-pipelines/database"
+	"github.com/gavswe19/ice-pipelines/model"
+	"github.com/gavswe19/ice-pipelines/nhlapi"
 )
 
-func processTeamRoster(tx *sql.Tx, season string) TeamsResponse {
-	response, err := http.Get(fmt.Sprintf("https://api-web.nhle.com/v1/standings/%s-01-01", season))
+// Set in main
+var nhlClient *nhlapi.Client
 
+// Teams are taken as they stood on April 1 of the season's end year, when
+// every season has been under way with its final alignment
+func processTeamRoster(ctx context.Context, tx *sql.Tx, season model.Season) ([]model.Team, error) {
+	teams, err := nhlClient.Teams(ctx, time.Date(season.EndYear(), 4, 1, 0, 0, 0, 0, time.UTC))
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
-
-	responseData, err := ioutil.ReadAll(response.Body)
-
-	if err != nil {
-		log.Fatal(err)
+	if len(teams) == 0 {
+		return nil, fmt.Errorf("no teams for season %s", season)
 	}
 
-	var teamsResponse TeamsResponse
-	json.Unmarshal(responseData, &teamsResponse)
-
-	insertTeamSeasons(tx, teamsResponse.Teams, season)
-	for _, team := range teamsResponse.Teams {
-		rosterPlayers := getRosterPlayers(team.Id, season)
-		insertPlayers(tx, rosterPlayers)
-		insertTeamSeasonPlayers(tx, rosterPlayers, team.Id, season)
+	if err := insertTeamSeasons(tx, teams, season); err != nil {
+		return nil, err
 	}
-
-	return teamsResponse
-}
-
-func getRosterPlayers(teamAbbrev string, season string) []PlayerObj {
-	response, err := http.Get(fmt.Sprintf("https://api-web.nhle.com/v1/roster/%s/%d", teamAbbrev, season))
-
-	if err != nil {
-		log.Fatal(err)
+	for _, team := range teams {
+		rosterPlayers, err := getRosterPlayers(ctx, team.Abbrev, season)
+		if err != nil {
+			return nil, err
+		}
+		if err := insertPlayers(tx, rosterPlayers); err != nil {
+			return nil, err
+		}
+		if err := insertTeamSeasonPlayers(tx, rosterPlayers, team.Id, season); err != nil {
+			return nil, err
+		}
 	}
 
-	responseData, err := ioutil.ReadAll(response.Body)
+	return teams, nil
+}
 
+func getRosterPlayers(ctx context.Context, teamAbbrev string, season model.Season) ([]model.Player, error) {
+	rosterResponse, err := nhlClient.Roster(ctx, teamAbbrev, season)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 
-	var rosterResponse RosterResponse
-	json.Unmarshal(responseData, &rosterResponse)
-	return rosterResponse.Players
+	return rosterResponse.ToPlayers(), nil
 }
 
-func insertPlayers(tx *sql.Tx, players []PlayerObj) {
+func insertPlayers(tx *sql.Tx, players []model.Player) error {
 	playerStrings := make([]string, 0, len(players))
 	playerValueArgs := make([]interface{}, 0, len(players)*3)
 
 	for _, player := range players {
 		playerStrings = append(playerStrings, "(?, ?, ?)")
 		playerValueArgs = append(playerValueArgs,
-			player.Player.PlayerId,
-			player.Player.FullName,
-			player.Position.PositionCode,
+			player.Id,
+			player.FullName(),
+			player.Position,
 		)
 	}
 
@@ -74,16 +73,15 @@ func insertPlayers(tx *sql.Tx, players []PlayerObj) {
 	result, err := tx.Exec(stmt, playerValueArgs...)
 
 	if err != nil {
-		tx.Rollback()
-		fmt.Println("Rolling back")
-		log.Fatal(err)
+		return fmt.Errorf("failed to insert into players: %w", err)
 	}
 
 	rows_affected, err := result.RowsAffected()
 	println(fmt.Sprintf("Inserted %s records into players", strconv.Itoa(int(rows_affected))))
+	return nil
 }
 
-func insertTeamSeasonPlayers(tx *sql.Tx, players []PlayerObj, teamId int, season string) {
+func insertTeamSeasonPlayers(tx *sql.Tx, players []model.Player, teamId int, season model.Season) error {
 	teamSeasonPlayerStrings := make([]string, 0, len(players))
 	teamSeasonPlayerValueArgs := make([]interface{}, 0, len(players)*3)
 
@@ -92,7 +90,7 @@ func insertTeamSeasonPlayers(tx *sql.Tx, players []PlayerObj, teamId int, season
 		teamSeasonPlayerValueArgs = append(teamSeasonPlayerValueArgs,
 			teamId,
 			season,
-			player.Player.PlayerId,
+			player.Id,
 		)
 	}
 
@@ -100,16 +98,15 @@ func insertTeamSeasonPlayers(tx *sql.Tx, players []PlayerObj, teamId int, season
 	result, err := tx.Exec(stmt, teamSeasonPlayerValueArgs...)
 
 	if err != nil {
-		tx.Rollback()
-		fmt.Println("Rolling back")
-		log.Fatal(err)
+		return fmt.Errorf("failed to insert into team_season_players: %w", err)
 	}
 
 	rows_affected, err := result.RowsAffected()
 	println(fmt.Sprintf("Inserted %s records into team_season_players", strconv.Itoa(int(rows_affected))))
+	return nil
 }
 
-func insertTeamSeasons(tx *sql.Tx, teams []Team, season string) {
+func insertTeamSeasons(tx *sql.Tx, teams []model.Team, season model.Season) error {
 	teamSeasonsStrings := make([]string, 0, len(teams))
 	teamSeasonsValueArgs := make([]interface{}, 0, len(teams)*9)
 
@@ -119,7 +116,7 @@ func insertTeamSeasons(tx *sql.Tx, teams []Team, season string) {
 			season,
 			team.Id,
 			team.Name,
-			team.Abbreviation,
+			team.Abbrev,
 			team.Division.Id,
 			team.Division.Name,
 			team.Conference.Id,
@@ -132,37 +129,38 @@ func insertTeamSeasons(tx *sql.Tx, teams []Team, season string) {
 	result, err := tx.Exec(stmt, teamSeasonsValueArgs...)
 
 	if err != nil {
-		tx.Rollback()
-		fmt.Println("Rolling back")
-		log.Fatal(err)
+		return fmt.Errorf("failed to insert into team_seasons: %w", err)
 	}
 
 	rows_affected, err := result.RowsAffected()
 	println(fmt.Sprintf("Inserted %s records into team_seasons", strconv.Itoa(int(rows_affected))))
+	return nil
 }
 
 func main() {
-	secrets := getAwsSecrets()
-
-	cfg := mysql.Config{
-		User:                 secrets.Username,
-		Passwd:               secrets.Password,
-		Net:                  "tcp",
-		Addr:                 "farm.cxqsjcdo8n1w.us-east-1.rds.amazonaws.com",
-		DBName:               "ICE",
-		AllowNativePasswords: true,
+	db, err := database.GetDatabase()
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
 	}
 
-	db, err := sql.Open("mysql", cfg.FormatDSN())
+	nhlClient, err = nhlapi.FromEnv(context.TODO())
 	if err != nil {
 		log.Fatal(err)
 	}
 
 	tx, err := db.Begin()
+	if err != nil {
+		log.Fatal(err)
+	}
 	println("Start Transation")
 
-	season := "20202021"
-	processTeamRoster(tx, season)
+	season := model.Season(20202021)
+	_, err = processTeamRoster(context.TODO(), tx, season)
+	if err != nil {
+		tx.Rollback()
+		fmt.Println("Rolling back")
+		log.Fatal(err)
+	}
 
 	err = tx.Commit()
 	if err != nil {
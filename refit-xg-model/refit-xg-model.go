@@ -0,0 +1,111 @@
+package main
+
+import (
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/gavswe19/ice-pipelines/database"
+	"github.com/gavswe19/ice-pipelines/model"
+	"github.com/gavswe19/ice-pipelines/xg"
+)
+
+// Refits the xG model from historical play_by_play rows and writes the
+// coefficients to a file that process-game can load via XG_MODEL_PATH
+func main() {
+	seasons := flag.String("seasons", "20212022,20222023,20232024", "comma separated seasons to fit on")
+	out := flag.String("out", "xg/coefficients.json", "path to write the fitted coefficients to")
+	version := flag.String("version", "", "version tag stored with the coefficients")
+	flag.Parse()
+
+	seasonList := strings.Split(*seasons, ",")
+	if *version == "" {
+		*version = fmt.Sprintf("fit-%s", strings.Join(seasonList, "-"))
+	}
+
+	db, err := database.GetDatabase()
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+
+	shots, err := fetchShots(db, seasonList)
+	if err != nil {
+		log.Fatalf("Failed to load shots: %v", err)
+	}
+	fmt.Printf("Fitting xG model on %d shots\n", len(shots))
+
+	xgModel, err := xg.Fit(shots, *version)
+	if err != nil {
+		log.Fatalf("Failed to fit xG model: %v", err)
+	}
+
+	if err := xgModel.Save(*out); err != nil {
+		log.Fatalf("Failed to save xG model: %v", err)
+	}
+	fmt.Printf("Wrote xG model %s to %s\n", xgModel.Version, *out)
+}
+
+func fetchShots(db *sql.DB, seasons []string) ([]xg.Shot, error) {
+	placeholders := make([]string, len(seasons))
+	args := make([]interface{}, len(seasons))
+	for i, season := range seasons {
+		placeholders[i] = "?"
+		args[i] = season
+	}
+
+	query := fmt.Sprintf(`
+	SELECT pbp.game_pk, pbp.event_idx, pbp.period, pbp.period_type, pbp.period_time, pbp.event_type_id,
+		pbp.secondary_type, pbp.team_id, pbp.normalized_x, pbp.normalized_y, pbp.strength_state, pbp.empty_net
+	FROM play_by_play pbp
+	JOIN games g ON g.game_pk = pbp.game_pk
+	WHERE g.season IN (%s)
+	ORDER BY pbp.game_pk, pbp.event_idx`, strings.Join(placeholders, ","))
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query play_by_play: %w", err)
+	}
+	defer rows.Close()
+
+	var shots []xg.Shot
+	var gameEvents []xg.Event
+
+	for rows.Next() {
+		var event xg.Event
+		var periodTime string
+		var strengthState sql.NullString
+		var emptyNet sql.NullBool
+
+		err := rows.Scan(
+			&event.GamePk,
+			&event.EventIdx,
+			&event.Period,
+			&event.PeriodType,
+			&periodTime,
+			&event.EventTypeId,
+			&event.SecondaryType,
+			&event.TeamId,
+			&event.X,
+			&event.Y,
+			&strengthState,
+			&emptyNet,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan play_by_play row: %w", err)
+		}
+		event.PeriodSeconds = model.PeriodTimeToSeconds(periodTime)
+		event.StrengthState = strengthState.String
+		event.EmptyNet = emptyNet.Bool
+
+		if len(gameEvents) > 0 && gameEvents[0].GamePk != event.GamePk {
+			shots = append(shots, xg.BuildShots(gameEvents)...)
+			gameEvents = gameEvents[:0]
+		}
+		gameEvents = append(gameEvents, event)
+	}
+	shots = append(shots, xg.BuildShots(gameEvents)...)
+
+	return shots, rows.Err()
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/gavswe19/ice-pipelines/database"
+	"github.com/gavswe19/ice-pipelines/jobs"
+	"github.com/gavswe19/ice-pipelines/message"
+)
+
+const gameQueueUrl = "https://sqs.us-east-1.amazonaws.com/271463937680/ice-game-queue"
+
+// Lists games whose last run failed or that have been stuck IN_PROGRESS past
+// jobs.StaleAfter, and with -requeue sends them back to the game queue
+func main() {
+	requeue := flag.Bool("requeue", false, "send the listed games back to the game queue")
+	flag.Parse()
+
+	db, err := database.GetDatabase()
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+
+	now := time.Now().UTC()
+	gameJobs, err := jobs.ListUnfinishedGameJobs(db, now)
+	if err != nil {
+		log.Fatalf("Failed to list games: %v", err)
+	}
+
+	for _, job := range gameJobs {
+		startedAt := "never"
+		if job.StartedAt != nil {
+			startedAt = job.StartedAt.Format(time.RFC3339)
+		}
+		status := job.Status
+		if job.IsStale(now) {
+			status = "STUCK"
+		}
+		fmt.Printf("%d %s attempts=%d started=%s version=%s error=%q\n",
+			job.GamePk, status, job.Attempts, startedAt, job.PipelineVersion, job.LastError)
+	}
+	fmt.Printf("%d failed or stuck games\n", len(gameJobs))
+
+	if !*requeue || len(gameJobs) == 0 {
+		return
+	}
+
+	ctx := context.TODO()
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	sqsClient := sqs.NewFromConfig(cfg)
+
+	for _, job := range gameJobs {
+		body, err := message.New(message.EntityGame, job.GamePk, "requeue-games").Encode()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		queueMessage := &sqs.SendMessageInput{
+			MessageBody: aws.String(body),
+			QueueUrl:    aws.String(gameQueueUrl),
+		}
+		_, err = sqsClient.SendMessage(ctx, queueMessage)
+
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+	fmt.Printf("Requeued %d games\n", len(gameJobs))
+}
@@ -0,0 +1,153 @@
+package xg
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+const maxFitIterations = 25
+
+const fitTolerance = 1e-6
+
+// L2 penalty keeping sparse one-hot features from running away
+const ridgePenalty = 1.0
+
+// Fits a logistic regression to the shots by iteratively reweighted least
+// squares and returns it as a Model
+func Fit(shots []Shot, version string) (Model, error) {
+	if len(shots) == 0 {
+		return Model{}, fmt.Errorf("no shots to fit xG model")
+	}
+
+	names := featureNames(shots)
+	rows := make([][]float64, len(shots))
+	labels := make([]float64, len(shots))
+
+	for i, shot := range shots {
+		features := shot.Features()
+		row := make([]float64, len(names)+1)
+		row[0] = 1
+		for j, name := range names {
+			row[j+1] = features[name]
+		}
+		rows[i] = row
+
+		if shot.IsGoal {
+			labels[i] = 1
+		}
+	}
+
+	beta := make([]float64, len(names)+1)
+
+	for iteration := 0; iteration < maxFitIterations; iteration++ {
+		hessian := make([][]float64, len(beta))
+		gradient := make([]float64, len(beta))
+		for i := range hessian {
+			hessian[i] = make([]float64, len(beta))
+			if i > 0 {
+				hessian[i][i] = ridgePenalty
+				gradient[i] = -ridgePenalty * beta[i]
+			}
+		}
+
+		for i, row := range rows {
+			p := sigmoid(dot(beta, row))
+			w := p * (1 - p)
+			for j := range row {
+				gradient[j] += (labels[i] - p) * row[j]
+				for k := range row {
+					hessian[j][k] += w * row[j] * row[k]
+				}
+			}
+		}
+
+		step, err := solve(hessian, gradient)
+		if err != nil {
+			return Model{}, fmt.Errorf("xG fit failed on iteration %d: %w", iteration, err)
+		}
+
+		change := 0.0
+		for j := range beta {
+			beta[j] += step[j]
+			change = math.Max(change, math.Abs(step[j]))
+		}
+
+		if change < fitTolerance {
+			break
+		}
+	}
+
+	model := Model{
+		Version:      version,
+		Intercept:    beta[0],
+		Coefficients: make(map[string]float64, len(names)),
+	}
+	for j, name := range names {
+		model.Coefficients[name] = beta[j+1]
+	}
+
+	return model, nil
+}
+
+func featureNames(shots []Shot) []string {
+	seen := make(map[string]bool)
+	for _, shot := range shots {
+		for name := range shot.Features() {
+			seen[name] = true
+		}
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func dot(a []float64, b []float64) float64 {
+	sum := 0.0
+	for i := range a {
+		sum += a[i] * b[i]
+	}
+	return sum
+}
+
+// Solves a x = b by Gaussian elimination with partial pivoting
+func solve(a [][]float64, b []float64) ([]float64, error) {
+	n := len(b)
+
+	for col := 0; col < n; col++ {
+		pivot := col
+		for row := col + 1; row < n; row++ {
+			if math.Abs(a[row][col]) > math.Abs(a[pivot][col]) {
+				pivot = row
+			}
+		}
+		if math.Abs(a[pivot][col]) < 1e-12 {
+			return nil, fmt.Errorf("singular matrix")
+		}
+		a[col], a[pivot] = a[pivot], a[col]
+		b[col], b[pivot] = b[pivot], b[col]
+
+		for row := col + 1; row < n; row++ {
+			factor := a[row][col] / a[col][col]
+			for k := col; k < n; k++ {
+				a[row][k] -= factor * a[col][k]
+			}
+			b[row] -= factor * b[col]
+		}
+	}
+
+	x := make([]float64, n)
+	for row := n - 1; row >= 0; row-- {
+		sum := b[row]
+		for k := row + 1; k < n; k++ {
+			sum -= a[row][k] * x[k]
+		}
+		x[row] = sum / a[row][row]
+	}
+
+	return x, nil
+}
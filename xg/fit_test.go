@@ -0,0 +1,64 @@
+package xg
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// Shots drawn from a known model, with goals sampled from its probabilities
+func syntheticShots(truth Model, count int) []Shot {
+	random := rand.New(rand.NewSource(1))
+	shots := make([]Shot, count)
+	for i := range shots {
+		shot := Shot{
+			Distance: 5 + random.Float64()*60,
+			Angle:    random.Float64() * 80,
+			Rebound:  random.Float64() < 0.2,
+		}
+		shot.IsGoal = random.Float64() < truth.Predict(shot)
+		shots[i] = shot
+	}
+	return shots
+}
+
+func TestFitRecoversKnownCoefficients(t *testing.T) {
+	truth := Model{
+		Intercept: -1,
+		Coefficients: map[string]float64{
+			"distance": -0.05,
+			"angle":    -0.01,
+			"rebound":  1,
+		},
+	}
+
+	fitted, err := Fit(syntheticShots(truth, 50000), "test")
+	if err != nil {
+		t.Fatalf("Fit: %v", err)
+	}
+
+	if fitted.Version != "test" {
+		t.Errorf("Version = %q, want %q", fitted.Version, "test")
+	}
+	if math.Abs(fitted.Intercept-truth.Intercept) > 0.1 {
+		t.Errorf("Intercept = %.4f, want %.4f", fitted.Intercept, truth.Intercept)
+	}
+
+	tolerances := map[string]float64{"distance": 0.005, "angle": 0.005, "rebound": 0.1}
+	for name, want := range truth.Coefficients {
+		got, ok := fitted.Coefficients[name]
+		if !ok {
+			t.Errorf("no coefficient fitted for %s", name)
+			continue
+		}
+		if math.Abs(got-want) > tolerances[name] {
+			t.Errorf("coefficient %s = %.4f, want %.4f", name, got, want)
+		}
+	}
+}
+
+func TestFitWithoutShots(t *testing.T) {
+	if _, err := Fit(nil, "test"); err == nil {
+		t.Error("Fit with no shots succeeded, want an error")
+	}
+}
@@ -0,0 +1,80 @@
+package xg
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+)
+
+//go:embed coefficients.json
+var defaultCoefficients []byte
+
+// Environment variable pointing at a coefficients file that overrides the
+// embedded default
+const ModelPathEnv = "XG_MODEL_PATH"
+
+// Logistic regression coefficients keyed by feature name
+type Model struct {
+	Version      string             `json:"version"`
+	Intercept    float64            `json:"intercept"`
+	Coefficients map[string]float64 `json:"coefficients"`
+}
+
+func DefaultModel() (Model, error) {
+	var model Model
+	if err := json.Unmarshal(defaultCoefficients, &model); err != nil {
+		return model, fmt.Errorf("failed to parse default xG coefficients: %w", err)
+	}
+	return model, nil
+}
+
+func LoadModel(path string) (Model, error) {
+	var model Model
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return model, fmt.Errorf("failed to read xG coefficients file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &model); err != nil {
+		return model, fmt.Errorf("failed to parse xG coefficients file: %w", err)
+	}
+
+	return model, nil
+}
+
+// Loads the model named by XG_MODEL_PATH, or the embedded default if unset
+func CurrentModel() (Model, error) {
+	if path := os.Getenv(ModelPathEnv); path != "" {
+		return LoadModel(path)
+	}
+	return DefaultModel()
+}
+
+func (m Model) Save(path string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode xG coefficients: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write xG coefficients file: %w", err)
+	}
+
+	return nil
+}
+
+// Probability that the shot is a goal
+func (m Model) Predict(shot Shot) float64 {
+	z := m.Intercept
+	for name, value := range shot.Features() {
+		z += m.Coefficients[name] * value
+	}
+	return sigmoid(z)
+}
+
+func sigmoid(z float64) float64 {
+	return 1 / (1 + math.Exp(-z))
+}
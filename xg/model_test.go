@@ -0,0 +1,59 @@
+package xg
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPredict(t *testing.T) {
+	m := Model{
+		Intercept: -1,
+		Coefficients: map[string]float64{
+			"distance":         -0.05,
+			"angle":            -0.01,
+			"rebound":          1,
+			"empty_net":        2.5,
+			"shot_type:wrist":  0.1,
+			"strength:5v4":     0.3,
+			"feature:not-seen": 100,
+		},
+	}
+
+	tests := []struct {
+		name string
+		shot Shot
+		want float64
+	}{
+		// z = -1 - 0.05*20 = -2
+		{"distance only", Shot{Distance: 20}, 0.11920292202211755},
+		// z = -1 - 0.05*20 - 0.01*45 + 1 = -1.45
+		{"angled rebound", Shot{Distance: 20, Angle: 45, Rebound: true}, 0.19000156601531298},
+		// z = -1 - 0.05*20 + 0.1 + 0.3 = -1.6
+		{"categorical features", Shot{Distance: 20, ShotType: "wrist", StrengthState: "5v4"}, 0.16798161486607552},
+		// Categories without a coefficient add nothing
+		{"unknown categories", Shot{Distance: 20, ShotType: "bank", StrengthState: "6v5"}, 0.11920292202211755},
+		// z = -1 - 0.05*20 + 2.5 = 0.5
+		{"empty net", Shot{Distance: 20, EmptyNet: true}, 0.6224593312018546},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := m.Predict(test.shot); math.Abs(got-test.want) > 1e-12 {
+				t.Errorf("Predict = %.12f, want %.12f", got, test.want)
+			}
+		})
+	}
+}
+
+func TestDefaultModel(t *testing.T) {
+	m, err := DefaultModel()
+	if err != nil {
+		t.Fatalf("DefaultModel: %v", err)
+	}
+
+	near := m.Predict(Shot{Distance: 10})
+	far := m.Predict(Shot{Distance: 60})
+	if near <= far || near >= 1 || far <= 0 {
+		t.Errorf("default model scored a 10ft shot %.4f and a 60ft shot %.4f", near, far)
+	}
+}
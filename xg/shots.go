@@ -0,0 +1,202 @@
+package xg
+
+import (
+	"math"
+	"strings"
+)
+
+// Goal line sits 89 feet from centre ice on either end
+const goalLineX = 89.0
+
+// Window, in seconds, for a shot to count as a rebound of the previous
+// attempt. Goalie rebound splits use the same window.
+const ReboundSeconds = 3
+
+// Window, in seconds, for a shot to count as off the rush
+const rushSeconds = 4
+
+// Blue lines sit 25 feet from centre ice. Faceoff zones use the same lines.
+const BlueLineX = 25.0
+
+// A play_by_play row with the fields the model needs. X and Y are the
+// normalized coordinates, with the shooting team attacking +x for shot
+// attempts and the event's team otherwise. TeamId is as stored, so blocked
+// shots carry the blocking team.
+type Event struct {
+	GamePk        int
+	EventIdx      int
+	Period        int
+	PeriodType    string
+	PeriodSeconds int
+	EventTypeId   string
+	SecondaryType string
+	TeamId        int
+	X             float64
+	Y             float64
+	StrengthState string
+	EmptyNet      bool
+}
+
+// An unblocked shot attempt and its model features
+type Shot struct {
+	GamePk        int
+	EventIdx      int
+	TeamId        int
+	Distance      float64
+	Angle         float64
+	ShotType      string
+	Rebound       bool
+	Rush          bool
+	StrengthState string
+	EmptyNet      bool
+	IsGoal        bool
+}
+
+func isUnblockedAttempt(eventTypeId string) bool {
+	return eventTypeId == "SHOT" || eventTypeId == "MISSED_SHOT" || eventTypeId == "GOAL"
+}
+
+func isAttempt(eventTypeId string) bool {
+	return isUnblockedAttempt(eventTypeId) || eventTypeId == "BLOCKED_SHOT"
+}
+
+// Builds a Shot for every unblocked attempt in a single game's events, which
+// must be in eventIdx order. Shootout attempts are skipped.
+func BuildShots(events []Event) []Shot {
+	var shots []Shot
+	teamIds := gameTeamIds(events)
+
+	for i, event := range events {
+		if !isUnblockedAttempt(event.EventTypeId) || event.PeriodType == "SHOOTOUT" {
+			continue
+		}
+
+		shot := Shot{
+			GamePk:        event.GamePk,
+			EventIdx:      event.EventIdx,
+			TeamId:        event.TeamId,
+			Distance:      DistanceToNet(event.X, event.Y),
+			Angle:         AngleToNet(event.X, event.Y),
+			ShotType:      NormalizeShotType(event.SecondaryType),
+			StrengthState: event.StrengthState,
+			EmptyNet:      event.EmptyNet,
+			IsGoal:        event.EventTypeId == "GOAL",
+		}
+
+		if i > 0 {
+			previous := events[i-1]
+			if previous.Period == event.Period {
+				elapsed := event.PeriodSeconds - previous.PeriodSeconds
+				previousTeamId := ShootingTeamId(previous.EventTypeId, previous.TeamId, teamIds)
+				shot.Rebound = elapsed <= ReboundSeconds && isAttempt(previous.EventTypeId) && previousTeamId == event.TeamId
+				shot.Rush = elapsed <= rushSeconds && !shot.Rebound && leftOffensiveZone(previous, previousTeamId, event)
+			}
+		}
+
+		shots = append(shots, shot)
+	}
+
+	return shots
+}
+
+// Distance in feet from normalized coordinates to the net being attacked
+func DistanceToNet(x float64, y float64) float64 {
+	return math.Hypot(goalLineX-x, y)
+}
+
+// Angle in degrees off the goal line's perpendicular, either side
+func AngleToNet(x float64, y float64) float64 {
+	return math.Atan2(math.Abs(y), goalLineX-x) * 180 / math.Pi
+}
+
+// Team credited with a shot attempt. statsapi tagged blocked shots with the
+// blocking team, and gamecenter plays are loaded the same way.
+func ShootingTeamId(eventTypeId string, teamId int, teamIds [2]int) int {
+	if eventTypeId != "BLOCKED_SHOT" {
+		return teamId
+	}
+	if teamIds[0] == teamId {
+		return teamIds[1]
+	}
+	return teamIds[0]
+}
+
+// The two teams appearing in a game's events
+func gameTeamIds(events []Event) [2]int {
+	var teamIds [2]int
+	for _, event := range events {
+		switch {
+		case event.TeamId == 0 || event.TeamId == teamIds[0]:
+		case teamIds[0] == 0:
+			teamIds[0] = event.TeamId
+		default:
+			teamIds[1] = event.TeamId
+			return teamIds
+		}
+	}
+	return teamIds
+}
+
+// The previous event was outside the shooting team's offensive zone. Events
+// by the other team are normalized to their direction of attack, so flip them.
+func leftOffensiveZone(previous Event, previousTeamId int, shot Event) bool {
+	x := previous.X
+	if previousTeamId != shot.TeamId {
+		x = -x
+	}
+	return x < BlueLineX
+}
+
+// Folds statsapi ("Wrist Shot") and gamecenter ("wrist") shot types together
+func NormalizeShotType(shotType string) string {
+	shotType = strings.ToLower(strings.TrimSpace(shotType))
+	shotType = strings.TrimSuffix(shotType, " shot")
+	shotType = strings.ReplaceAll(shotType, " ", "-")
+
+	switch shotType {
+	case "tip":
+		return "tip-in"
+	case "wrap":
+		return "wrap-around"
+	}
+	return shotType
+}
+
+// Classifies a shot location, in normalized coordinates, as "high" (inner
+// slot), "medium" (extended slot out to the top of the circles) or "low"
+func DangerZone(x float64, y float64) string {
+	switch {
+	case x >= 69 && x <= goalLineX && math.Abs(y) <= 9:
+		return "high"
+	case x >= 54 && x <= goalLineX && math.Abs(y) <= 22:
+		return "medium"
+	}
+	return "low"
+}
+
+// Named features fed to the model. Categorical features are one-hot encoded
+// as "name:value" so coefficient files only need entries they care about.
+func (s Shot) Features() map[string]float64 {
+	features := map[string]float64{
+		"distance": s.Distance,
+		"angle":    s.Angle,
+	}
+
+	if s.Rebound {
+		features["rebound"] = 1
+	}
+	if s.Rush {
+		features["rush"] = 1
+	}
+	if s.EmptyNet {
+		features["empty_net"] = 1
+	}
+	if s.ShotType != "" {
+		features["shot_type:"+s.ShotType] = 1
+	}
+	if s.StrengthState != "" {
+		features["strength:"+s.StrengthState] = 1
+	}
+
+	return features
+}
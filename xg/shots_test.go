@@ -0,0 +1,184 @@
+package xg
+
+import (
+	"math"
+	"testing"
+)
+
+const (
+	testShooterTeamId  = 1
+	testDefenderTeamId = 2
+)
+
+func TestDistanceAndAngleToNet(t *testing.T) {
+	tests := []struct {
+		x, y     float64
+		distance float64
+		angle    float64
+	}{
+		{59, 0, 30, 0},
+		{80, 9, math.Hypot(9, 9), 45},
+		{80, -9, math.Hypot(9, 9), 45},
+		{89, 10, 10, 90},
+		{-89, 0, 178, 0},
+	}
+
+	for _, test := range tests {
+		if got := DistanceToNet(test.x, test.y); math.Abs(got-test.distance) > 1e-9 {
+			t.Errorf("DistanceToNet(%v, %v) = %.4f, want %.4f", test.x, test.y, got, test.distance)
+		}
+		if got := AngleToNet(test.x, test.y); math.Abs(got-test.angle) > 1e-9 {
+			t.Errorf("AngleToNet(%v, %v) = %.4f, want %.4f", test.x, test.y, got, test.angle)
+		}
+	}
+}
+
+func TestBuildShotsRebound(t *testing.T) {
+	tests := []struct {
+		name     string
+		previous Event
+		want     bool
+	}{
+		{"own shot", Event{Period: 1, PeriodSeconds: 100, EventTypeId: "SHOT", TeamId: testShooterTeamId, X: 60}, true},
+		{"own missed shot", Event{Period: 1, PeriodSeconds: 100, EventTypeId: "MISSED_SHOT", TeamId: testShooterTeamId, X: 60}, true},
+		// Blocked shots are stored against the blocking team
+		{"own blocked shot", Event{Period: 1, PeriodSeconds: 100, EventTypeId: "BLOCKED_SHOT", TeamId: testDefenderTeamId, X: 60}, true},
+		{"opponent's shot", Event{Period: 1, PeriodSeconds: 100, EventTypeId: "SHOT", TeamId: testDefenderTeamId, X: 60}, false},
+		{"not an attempt", Event{Period: 1, PeriodSeconds: 100, EventTypeId: "HIT", TeamId: testShooterTeamId, X: 60}, false},
+		{"outside the window", Event{Period: 1, PeriodSeconds: 100 - ReboundSeconds, EventTypeId: "SHOT", TeamId: testShooterTeamId, X: 60}, false},
+		{"previous period", Event{Period: 0, PeriodSeconds: 100, EventTypeId: "SHOT", TeamId: testShooterTeamId, X: 60}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			shot := buildFollowUpShot(t, test.previous)
+			if shot.Rebound != test.want {
+				t.Errorf("Rebound = %t, want %t", shot.Rebound, test.want)
+			}
+			if shot.Rebound && shot.Rush {
+				t.Error("a rebound was also flagged as a rush")
+			}
+		})
+	}
+}
+
+func TestBuildShotsRush(t *testing.T) {
+	tests := []struct {
+		name     string
+		previous Event
+		want     bool
+	}{
+		{"own defensive zone", Event{Period: 1, PeriodSeconds: 101, EventTypeId: "TAKEAWAY", TeamId: testShooterTeamId, X: -60}, true},
+		{"own neutral zone", Event{Period: 1, PeriodSeconds: 101, EventTypeId: "TAKEAWAY", TeamId: testShooterTeamId, X: 0}, true},
+		{"own offensive zone", Event{Period: 1, PeriodSeconds: 101, EventTypeId: "TAKEAWAY", TeamId: testShooterTeamId, X: 60}, false},
+		// The opponent's coordinates point the other way
+		{"opponent's offensive zone", Event{Period: 1, PeriodSeconds: 101, EventTypeId: "GIVEAWAY", TeamId: testDefenderTeamId, X: 60}, true},
+		{"opponent's defensive zone", Event{Period: 1, PeriodSeconds: 101, EventTypeId: "GIVEAWAY", TeamId: testDefenderTeamId, X: -60}, false},
+		{"outside the window", Event{Period: 1, PeriodSeconds: 102 - rushSeconds - 1, EventTypeId: "TAKEAWAY", TeamId: testShooterTeamId, X: -60}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			shot := buildFollowUpShot(t, test.previous)
+			if shot.Rush != test.want {
+				t.Errorf("Rush = %t, want %t", shot.Rush, test.want)
+			}
+		})
+	}
+}
+
+// Builds the shot taken by testShooterTeamId at 102 seconds of the first
+// period, following previous. An opening faceoff by the defending team makes
+// both teams known.
+func buildFollowUpShot(t *testing.T, previous Event) Shot {
+	t.Helper()
+
+	events := []Event{
+		{EventIdx: 0, Period: 1, PeriodSeconds: 0, EventTypeId: "FACEOFF", TeamId: testDefenderTeamId},
+		previous,
+		{EventIdx: 2, Period: 1, PeriodSeconds: 102, EventTypeId: "SHOT", TeamId: testShooterTeamId, X: 80, Y: 9},
+	}
+	events[1].EventIdx = 1
+
+	shots := BuildShots(events)
+	last := shots[len(shots)-1]
+	if last.EventIdx != 2 {
+		t.Fatalf("no shot built for the follow-up attempt: %+v", shots)
+	}
+	return last
+}
+
+func TestBuildShots(t *testing.T) {
+	events := []Event{
+		{GamePk: 1, EventIdx: 0, Period: 1, EventTypeId: "FACEOFF", TeamId: testShooterTeamId},
+		{GamePk: 1, EventIdx: 1, Period: 1, PeriodSeconds: 30, EventTypeId: "SHOT", SecondaryType: "Wrist Shot", TeamId: testShooterTeamId, X: 59, StrengthState: "5v4"},
+		{GamePk: 1, EventIdx: 2, Period: 1, PeriodSeconds: 60, EventTypeId: "BLOCKED_SHOT", TeamId: testDefenderTeamId, X: 60},
+		{GamePk: 1, EventIdx: 3, Period: 3, PeriodSeconds: 1190, EventTypeId: "GOAL", SecondaryType: "wrap", TeamId: testDefenderTeamId, X: 89, Y: 10, EmptyNet: true},
+		{GamePk: 1, EventIdx: 4, Period: 5, PeriodType: "SHOOTOUT", EventTypeId: "GOAL", TeamId: testShooterTeamId, X: 80},
+	}
+
+	shots := BuildShots(events)
+	if len(shots) != 2 {
+		t.Fatalf("BuildShots built %d shots, want 2 unblocked non-shootout attempts", len(shots))
+	}
+
+	want := []Shot{
+		{GamePk: 1, EventIdx: 1, TeamId: testShooterTeamId, Distance: 30, Angle: 0, ShotType: "wrist", StrengthState: "5v4"},
+		{GamePk: 1, EventIdx: 3, TeamId: testDefenderTeamId, Distance: 10, Angle: 90, ShotType: "wrap-around", EmptyNet: true, IsGoal: true},
+	}
+	for i := range want {
+		got := shots[i]
+		if math.Abs(got.Distance-want[i].Distance) > 1e-9 || math.Abs(got.Angle-want[i].Angle) > 1e-9 {
+			t.Errorf("shot %d distance, angle = %.4f, %.4f, want %.4f, %.4f", i, got.Distance, got.Angle, want[i].Distance, want[i].Angle)
+		}
+		got.Distance, got.Angle = want[i].Distance, want[i].Angle
+		if got != want[i] {
+			t.Errorf("shot %d = %+v, want %+v", i, got, want[i])
+		}
+	}
+}
+
+func TestFeatures(t *testing.T) {
+	shot := Shot{Distance: 20, Angle: 30, ShotType: "snap", StrengthState: "5v4", Rebound: true, Rush: true, EmptyNet: true}
+	want := map[string]float64{
+		"distance":       20,
+		"angle":          30,
+		"rebound":        1,
+		"rush":           1,
+		"empty_net":      1,
+		"shot_type:snap": 1,
+		"strength:5v4":   1,
+	}
+
+	got := shot.Features()
+	if len(got) != len(want) {
+		t.Errorf("Features = %v, want %v", got, want)
+	}
+	for name, value := range want {
+		if got[name] != value {
+			t.Errorf("feature %s = %v, want %v", name, got[name], value)
+		}
+	}
+
+	if got := (Shot{Distance: 20}).Features(); len(got) != 2 {
+		t.Errorf("Features of a plain shot = %v, want only distance and angle", got)
+	}
+}
+
+func TestNormalizeShotType(t *testing.T) {
+	tests := map[string]string{
+		"Wrist Shot":  "wrist",
+		"wrist":       "wrist",
+		"Tip-In":      "tip-in",
+		"tip":         "tip-in",
+		"Wrap-around": "wrap-around",
+		"wrap":        "wrap-around",
+		"Slap Shot":   "slap",
+		"":            "",
+	}
+	for shotType, want := range tests {
+		if got := NormalizeShotType(shotType); got != want {
+			t.Errorf("NormalizeShotType(%q) = %q, want %q", shotType, got, want)
+		}
+	}
+}